@@ -1,7 +1,14 @@
 package datadog
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
 	"log"
+	"net/http"
+	"net/url"
+	"time"
 
 	"github.com/zorkian/go-datadog-api"
 )
@@ -10,6 +17,49 @@ import (
 type Config struct {
 	APIKey string
 	APPKey string
+	APIURL string
+
+	// MaxRetries, MaxRetryWait and RetryTimeout are the retry budget of
+	// every API call. RetryTimeout bounds a call including its retries.
+	MaxRetries   int
+	MaxRetryWait time.Duration
+	RetryTimeout time.Duration
+
+	// Validate makes Client check the keys with Datadog before returning.
+	Validate bool
+
+	// ProxyURL, when set, is used instead of the HTTP(S)_PROXY environment
+	// variables.
+	ProxyURL string
+	// CABundle is a PEM file with certificates to trust on top of the system
+	// ones.
+	CABundle string
+	// TLSMinVersion is one of the keys of tlsVersions.
+	TLSMinVersion string
+
+	// RequestTimeout bounds each HTTP request.
+	RequestTimeout time.Duration
+
+	// LogBodyLimit is how many bytes of each body are logged at DEBUG level.
+	// Zero logs bodies in full.
+	LogBodyLimit int
+}
+
+// providerMeta is handed to every resource as meta: the API client, and the
+// provider-wide settings resources apply to what they send.
+type providerMeta struct {
+	client *datadog.Client
+
+	// defaultTags are added to the tags of every monitor.
+	defaultTags []string
+}
+
+// tlsVersions maps the values accepted by tls_min_version to crypto/tls.
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
 }
 
 // Client returns a new Datadog client.
@@ -17,7 +67,98 @@ func (c *Config) Client() (*datadog.Client, error) {
 
 	client := datadog.NewClient(c.APIKey, c.APPKey)
 
-	log.Printf("[INFO] Datadog Client configured ")
+	if c.APIURL != "" {
+		client.SetBaseUrl(c.APIURL)
+	}
+
+	httpClient, err := c.httpClient()
+	if err != nil {
+		return nil, err
+	}
+	client.HttpClient = httpClient
+
+	client.MaxRetries = c.MaxRetries
+	client.MaxRetryWait = c.MaxRetryWait
+	client.RetryTimeout = c.RetryTimeout
+
+	if c.Validate {
+		if err := validateCredentials(client); err != nil {
+			return nil, err
+		}
+	}
+
+	log.Printf("[INFO] Datadog Client configured for %s", datadog.RedactUrl(client.GetBaseUrl()))
 
 	return client, nil
 }
+
+// validateCredentials checks the API and then the APP key, so a rejected key
+// fails once, up front, rather than for every resource.
+func validateCredentials(client *datadog.Client) error {
+	ok, err := client.Validate()
+	if err != nil {
+		return fmt.Errorf("error validating Datadog credentials: %s", err)
+	}
+	if !ok {
+		return fmt.Errorf("Datadog rejected the API key (api_key / DATADOG_API_KEY), check it is valid for %s",
+			datadog.RedactUrl(client.GetBaseUrl()))
+	}
+
+	ok, err = client.ValidateAppKey()
+	if err != nil {
+		return fmt.Errorf("error validating Datadog credentials: %s", err)
+	}
+	if !ok {
+		return fmt.Errorf("Datadog rejected the APP key (app_key / DATADOG_APP_KEY), check it is valid for %s",
+			datadog.RedactUrl(client.GetBaseUrl()))
+	}
+
+	return nil
+}
+
+// httpClient builds the *http.Client used for every API call. Its transport
+// is a clone of http.DefaultTransport with the proxy and TLS settings applied.
+func (c *Config) httpClient() (*http.Client, error) {
+	tlsConfig := &tls.Config{}
+
+	if c.TLSMinVersion != "" {
+		v, ok := tlsVersions[c.TLSMinVersion]
+		if !ok {
+			return nil, fmt.Errorf("unsupported TLS version %q", c.TLSMinVersion)
+		}
+		tlsConfig.MinVersion = v
+	}
+
+	if c.CABundle != "" {
+		pem, err := ioutil.ReadFile(c.CABundle)
+		if err != nil {
+			return nil, fmt.Errorf("error reading CA bundle: %s", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no PEM certificates found in CA bundle %s", c.CABundle)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	proxy := http.ProxyFromEnvironment
+	if c.ProxyURL != "" {
+		u, err := url.Parse(c.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing proxy URL: %s", err)
+		}
+		proxy = http.ProxyURL(u)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = proxy
+	transport.TLSClientConfig = tlsConfig
+
+	return &http.Client{
+		Transport: newLoggingTransport(transport, c.LogBodyLimit, c.APIKey, c.APPKey),
+		Timeout:   c.RequestTimeout,
+	}, nil
+}
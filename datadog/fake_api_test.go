@@ -0,0 +1,689 @@
+package datadog
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ojongerius/terraform-provider-datadog/query"
+)
+
+// fakeAPI is an in-process stand-in for the Datadog API. It keeps state for
+// the monitor, downtime, dashboard, screenboard, user and host tag endpoints
+// the client uses, so provider tests can run without real keys.
+//
+// Objects are stored as decoded JSON rather than client structs, so fields
+// the fake does not know about are kept and returned as they were sent.
+type fakeAPI struct {
+	*httptest.Server
+
+	APIKey string
+	APPKey string
+
+	mu           sync.Mutex
+	nextID       int
+	monitors     map[int]map[string]interface{}
+	downtimes    map[int]map[string]interface{}
+	dashboards   map[int]map[string]interface{}
+	screenboards map[int]map[string]interface{}
+	shares       map[int]int
+	users        map[string]map[string]interface{}
+	hostTags     map[string]map[string][]string
+	faults       []*fakeFault
+}
+
+// fakeFault makes the fake misbehave for matching requests.
+type fakeFault struct {
+	// Method and Path select the requests the fault applies to. An empty
+	// Method matches any method, Path matches by prefix of the URL path.
+	Method string
+	Path   string
+
+	// Delay is waited before answering. With a zero Status the request is
+	// then handled as usual.
+	Delay time.Duration
+
+	// Status, when set, is returned instead of handling the request, with
+	// Header added to the response.
+	Status int
+	Header map[string]string
+
+	// Count is how many requests the fault applies to, 0 means all of them.
+	Count int
+}
+
+func newFakeAPI() *fakeAPI {
+	f := &fakeAPI{
+		APIKey:       "fake-api-key",
+		APPKey:       "fake-app-key",
+		nextID:       1,
+		monitors:     make(map[int]map[string]interface{}),
+		downtimes:    make(map[int]map[string]interface{}),
+		dashboards:   make(map[int]map[string]interface{}),
+		screenboards: make(map[int]map[string]interface{}),
+		shares:       make(map[int]int),
+		users:        make(map[string]map[string]interface{}),
+		hostTags:     make(map[string]map[string][]string),
+	}
+	f.Server = httptest.NewServer(f)
+	return f
+}
+
+// Inject adds a fault, taking effect from the next request.
+func (f *fakeAPI) Inject(fault *fakeFault) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.faults = append(f.faults, fault)
+}
+
+// Monitor returns a copy of a stored monitor, or nil.
+func (f *fakeAPI) Monitor(id int) map[string]interface{} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return copyObject(f.monitors[id])
+}
+
+// Monitors returns how many monitors are stored.
+func (f *fakeAPI) Monitors() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.monitors)
+}
+
+// UpdateMonitor changes fields of a stored monitor, like an edit in the UI.
+func (f *fakeAPI) UpdateMonitor(id int, fields map[string]interface{}) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for k, v := range fields {
+		f.monitors[id][k] = v
+	}
+}
+
+// DeleteMonitor removes a stored monitor, like a delete in the UI.
+func (f *fakeAPI) DeleteMonitor(id int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.monitors, id)
+}
+
+// Meta returns provider meta with a client for the fake, for tests that call
+// resource functions directly rather than through resource.Test.
+func (f *fakeAPI) Meta(t *testing.T) *providerMeta {
+	c := Config{APIKey: f.APIKey, APPKey: f.APPKey, APIURL: f.URL}
+	client, err := c.Client()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	return &providerMeta{client: client}
+}
+
+// Downtime returns a copy of a stored downtime, or nil.
+func (f *fakeAPI) Downtime(id int) map[string]interface{} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return copyObject(f.downtimes[id])
+}
+
+// UpdateDowntime changes fields of a stored downtime, like Datadog does when
+// a downtime ends or moves on to its next occurrence.
+func (f *fakeAPI) UpdateDowntime(id int, fields map[string]interface{}) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for k, v := range fields {
+		f.downtimes[id][k] = v
+	}
+}
+
+// CancelDowntime cancels a stored downtime, like a cancel in the UI.
+func (f *fakeAPI) CancelDowntime(id int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.deleteDowntime(id)
+}
+
+// Dashboard returns a copy of a stored dashboard, or nil.
+func (f *fakeAPI) Dashboard(id int) map[string]interface{} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return copyObject(f.dashboards[id])
+}
+
+// UpdateDashboard changes fields of a stored dashboard, like an edit in the UI.
+func (f *fakeAPI) UpdateDashboard(id int, fields map[string]interface{}) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for k, v := range fields {
+		f.dashboards[id][k] = v
+	}
+}
+
+// Screenboard returns a copy of a stored screenboard, or nil.
+func (f *fakeAPI) Screenboard(id int) map[string]interface{} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return copyObject(f.screenboards[id])
+}
+
+// UpdateScreenboard changes fields of a stored screenboard, like an edit in
+// the UI.
+func (f *fakeAPI) UpdateScreenboard(id int, fields map[string]interface{}) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for k, v := range fields {
+		f.screenboards[id][k] = v
+	}
+}
+
+// Shares returns how many times screenboard id was shared.
+func (f *fakeAPI) Shares(id int) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.shares[id]
+}
+
+// User returns a copy of a stored user, or nil.
+func (f *fakeAPI) User(handle string) map[string]interface{} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return copyObject(f.users[handle])
+}
+
+// UpdateUser changes fields of a stored user, like the user accepting their
+// invite.
+func (f *fakeAPI) UpdateUser(handle string, fields map[string]interface{}) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for k, v := range fields {
+		f.users[handle][k] = v
+	}
+}
+
+func (f *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if fault := f.fault(r); fault != nil {
+		time.Sleep(fault.Delay)
+		if fault.Status != 0 {
+			for k, v := range fault.Header {
+				w.Header().Set(k, v)
+			}
+			fakeError(w, fault.Status, "injected fault")
+			return
+		}
+	}
+
+	if r.Header.Get("DD-API-KEY") != f.APIKey {
+		fakeError(w, http.StatusForbidden, "API key is invalid")
+		return
+	}
+	if r.URL.Path != "/api/v1/validate" && r.Header.Get("DD-APPLICATION-KEY") != f.APPKey {
+		fakeError(w, http.StatusForbidden, "Application key is invalid")
+		return
+	}
+
+	var body map[string]interface{}
+	if r.Body != nil && (r.Method == "POST" || r.Method == "PUT") {
+		dec := json.NewDecoder(r.Body)
+		dec.UseNumber()
+		if err := dec.Decode(&body); err != nil && err.Error() != "EOF" {
+			fakeError(w, http.StatusBadRequest, "invalid JSON: "+err.Error())
+			return
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	path := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/"), "/"), "/")
+	switch path[0] {
+	case "validate":
+		fakeJSON(w, map[string]interface{}{"valid": true})
+	case "monitor":
+		f.serveMonitor(w, r, path[1:], body)
+	case "downtime":
+		f.serveObjects(w, r, path[1:], body, f.downtimes, f.createDowntime, f.deleteDowntime)
+	case "dash":
+		f.serveDashboard(w, r, path[1:], body)
+	case "screen":
+		f.serveScreenboard(w, r, path[1:], body)
+	case "user", "invite_users":
+		f.serveUser(w, r, path, body)
+	case "tags":
+		f.serveHostTags(w, r, path[1:], body)
+	default:
+		fakeError(w, http.StatusNotFound, "unknown endpoint "+r.URL.Path)
+	}
+}
+
+// fault returns the first fault matching r, using up one of its requests.
+func (f *fakeAPI) fault(r *http.Request) *fakeFault {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for i, fault := range f.faults {
+		if fault.Method != "" && fault.Method != r.Method {
+			continue
+		}
+		if !strings.HasPrefix(r.URL.Path, fault.Path) {
+			continue
+		}
+		if fault.Count > 0 {
+			fault.Count--
+			if fault.Count == 0 {
+				f.faults = append(f.faults[:i], f.faults[i+1:]...)
+			}
+		}
+		return fault
+	}
+	return nil
+}
+
+func (f *fakeAPI) serveMonitor(w http.ResponseWriter, r *http.Request, path []string, body map[string]interface{}) {
+	switch {
+	case len(path) == 0 && r.Method == "GET":
+		name := r.URL.Query().Get("name")
+		list := []interface{}{}
+		for _, id := range sortedIDs(f.monitors) {
+			m := f.monitors[id]
+			if n, _ := m["name"].(string); strings.Contains(n, name) {
+				list = append(list, m)
+			}
+		}
+		fakeJSON(w, list)
+	case len(path) == 0 && r.Method == "POST":
+		if body["type"] == nil || body["query"] == nil {
+			fakeError(w, http.StatusBadRequest, "monitor type and query are required")
+			return
+		}
+		fakeJSON(w, f.store(f.monitors, fakeMonitorMetadata(body)))
+	case len(path) == 1 && (path[0] == "mute_all" || path[0] == "unmute_all"):
+		fakeJSON(w, map[string]interface{}{})
+	case len(path) == 2 && (path[1] == "mute" || path[1] == "unmute"):
+		m := f.monitors[fakeID(path[0])]
+		if m == nil {
+			fakeError(w, http.StatusNotFound, "Monitor not found")
+			return
+		}
+		fakeMute(m, path[1] == "mute", body)
+		fakeJSON(w, m)
+	case len(path) == 1 && r.Method == "DELETE" && f.usedByComposite(fakeID(path[0])):
+		fakeError(w, http.StatusBadRequest, "monitor is referenced in a composite monitor")
+	case len(path) == 1 && r.Method == "PUT" && f.monitors[fakeID(path[0])] != nil:
+		f.monitors[fakeID(path[0])]["modified"] = fakeTimestamp()
+		f.serveObjects(w, r, path, body, f.monitors, nil, nil)
+	case len(path) == 1:
+		f.serveObjects(w, r, path, body, f.monitors, nil, nil)
+	default:
+		fakeError(w, http.StatusNotFound, "unknown endpoint "+r.URL.Path)
+	}
+}
+
+// fakeMonitorMetadata adds the fields Datadog sets on a new monitor to m.
+// Like Datadog, a new monitor has no data until it is first evaluated.
+func fakeMonitorMetadata(m map[string]interface{}) map[string]interface{} {
+	now := fakeTimestamp()
+	m["overall_state"] = "No Data"
+	m["creator"] = map[string]interface{}{"id": 1, "email": "terraform@example.com",
+		"handle": "terraform@example.com", "name": "Terraform"}
+	m["created"] = now
+	m["modified"] = now
+	m["deleted"] = nil
+	return m
+}
+
+// fakeTimestamp returns the current time in the format Datadog uses.
+func fakeTimestamp() string {
+	return time.Now().UTC().Format("2006-01-02T15:04:05.000000-07:00")
+}
+
+// usedByComposite reports whether a composite monitor refers to monitor id,
+// in which case Datadog refuses to delete it.
+func (f *fakeAPI) usedByComposite(id int) bool {
+	for _, m := range f.monitors {
+		q, _ := m["query"].(string)
+		c, err := query.ParseComposite(q)
+		if m["type"] != "composite" || err != nil {
+			continue
+		}
+		for _, ref := range c.IDs() {
+			if ref == id {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// createDowntime starts a downtime now when it has no start, like Datadog.
+func (f *fakeAPI) createDowntime(d map[string]interface{}) {
+	d["active"] = true
+	d["disabled"] = false
+	if d["start"] == nil {
+		d["start"] = time.Now().Unix()
+	}
+}
+
+// deleteDowntime cancels rather than removes, like Datadog does.
+func (f *fakeAPI) deleteDowntime(id int) {
+	d := f.downtimes[id]
+	d["active"] = false
+	d["disabled"] = true
+	d["canceled"] = time.Now().Unix()
+}
+
+func (f *fakeAPI) serveDashboard(w http.ResponseWriter, r *http.Request, path []string, body map[string]interface{}) {
+	wrap := func(d map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{
+			"dash":     d,
+			"resource": fmt.Sprintf("/api/v1/dash/%v", d["id"]),
+			"url":      fmt.Sprintf("/dash/dash/%v", d["id"]),
+		}
+	}
+
+	switch {
+	case len(path) == 0 && r.Method == "GET":
+		list := []interface{}{}
+		for _, id := range sortedIDs(f.dashboards) {
+			d := f.dashboards[id]
+			list = append(list, map[string]interface{}{
+				"id":          strconv.Itoa(id),
+				"title":       d["title"],
+				"description": d["description"],
+				"resource":    fmt.Sprintf("/api/v1/dash/%d", id),
+			})
+		}
+		fakeJSON(w, map[string]interface{}{"dashes": list})
+	case len(path) == 0 && r.Method == "POST":
+		fakeJSON(w, wrap(f.store(f.dashboards, body)))
+	case len(path) == 1 && r.Method == "GET":
+		d := f.dashboards[fakeID(path[0])]
+		if d == nil {
+			fakeError(w, http.StatusNotFound, "Dashboard not found")
+			return
+		}
+		fakeJSON(w, wrap(d))
+	case len(path) == 1:
+		f.serveObjects(w, r, path, body, f.dashboards, nil, nil)
+	default:
+		fakeError(w, http.StatusNotFound, "unknown endpoint "+r.URL.Path)
+	}
+}
+
+func (f *fakeAPI) serveScreenboard(w http.ResponseWriter, r *http.Request, path []string, body map[string]interface{}) {
+	switch {
+	case len(path) == 0 && r.Method == "GET":
+		list := []interface{}{}
+		for _, id := range sortedIDs(f.screenboards) {
+			list = append(list, map[string]interface{}{
+				"id":       id,
+				"title":    f.screenboards[id]["board_title"],
+				"resource": fmt.Sprintf("/api/v1/screen/%d", id),
+			})
+		}
+		fakeJSON(w, map[string]interface{}{"screenboards": list})
+	case len(path) == 2 && path[0] == "share":
+		id := fakeID(path[1])
+		if f.screenboards[id] == nil {
+			fakeError(w, http.StatusNotFound, "Screenboard not found")
+			return
+		}
+		if r.Method == "DELETE" {
+			f.screenboards[id]["shared"] = false
+			fakeJSON(w, map[string]interface{}{})
+			return
+		}
+		f.shares[id]++
+		f.screenboards[id]["shared"] = true
+		fakeJSON(w, map[string]interface{}{
+			"board_id":   id,
+			"public_url": fmt.Sprintf("%s/sb/fake-%d", f.URL, id),
+		})
+	case len(path) == 1 && r.Method == "DELETE":
+		delete(f.shares, fakeID(path[0]))
+		f.serveObjects(w, r, path, body, f.screenboards, nil, nil)
+	default:
+		f.serveObjects(w, r, path, body, f.screenboards, nil, nil)
+	}
+}
+
+func (f *fakeAPI) serveUser(w http.ResponseWriter, r *http.Request, path []string, body map[string]interface{}) {
+	switch {
+	case path[0] == "invite_users" && r.Method == "POST":
+		emails, _ := body["emails"].([]interface{})
+		for _, e := range emails {
+			email := e.(string)
+			if _, ok := f.users[email]; !ok {
+				f.users[email] = map[string]interface{}{
+					"handle":   email,
+					"email":    email,
+					"role":     nil,
+					"is_admin": false,
+					"verified": false,
+					"disabled": false,
+				}
+			}
+		}
+		fakeJSON(w, map[string]interface{}{"emails": emails})
+	case path[0] == "user" && len(path) == 1 && r.Method == "GET":
+		list := []interface{}{}
+		handles := make([]string, 0, len(f.users))
+		for h := range f.users {
+			handles = append(handles, h)
+		}
+		sort.Strings(handles)
+		for _, h := range handles {
+			list = append(list, f.users[h])
+		}
+		fakeJSON(w, map[string]interface{}{"users": list})
+	case path[0] == "user" && len(path) == 2:
+		u := f.users[path[1]]
+		if u == nil {
+			fakeError(w, http.StatusNotFound, "User not found")
+			return
+		}
+		switch r.Method {
+		case "GET":
+		case "PUT":
+			for k, v := range body {
+				u[k] = v
+			}
+		case "DELETE":
+			// Datadog disables users rather than removing them.
+			u["disabled"] = true
+		}
+		fakeJSON(w, map[string]interface{}{"user": u})
+	default:
+		fakeError(w, http.StatusNotFound, "unknown endpoint "+r.URL.Path)
+	}
+}
+
+func (f *fakeAPI) serveHostTags(w http.ResponseWriter, r *http.Request, path []string, body map[string]interface{}) {
+	if len(path) == 0 || path[0] != "hosts" {
+		fakeError(w, http.StatusNotFound, "unknown endpoint "+r.URL.Path)
+		return
+	}
+	source := r.URL.Query().Get("source")
+	if source == "" {
+		source = "users"
+	}
+
+	if len(path) == 1 {
+		tags := make(map[string][]string)
+		for host, bySource := range f.hostTags {
+			for s, list := range bySource {
+				if r.URL.Query().Get("source") != "" && s != source {
+					continue
+				}
+				for _, t := range list {
+					tags[t] = append(tags[t], host)
+				}
+			}
+		}
+		fakeJSON(w, map[string]interface{}{"tags": tags})
+		return
+	}
+
+	host := path[1]
+	if f.hostTags[host] == nil {
+		f.hostTags[host] = make(map[string][]string)
+	}
+	var tags []string
+	if list, ok := body["tags"].([]interface{}); ok {
+		for _, t := range list {
+			tags = append(tags, t.(string))
+		}
+	}
+
+	switch r.Method {
+	case "POST":
+		f.hostTags[host][source] = append(f.hostTags[host][source], tags...)
+	case "PUT":
+		f.hostTags[host][source] = tags
+	case "DELETE":
+		delete(f.hostTags[host], source)
+		fakeJSON(w, map[string]interface{}{})
+		return
+	}
+
+	if r.URL.Query().Get("by_source") == "true" {
+		fakeJSON(w, map[string]interface{}{"tags": f.hostTags[host]})
+		return
+	}
+	all := []string{}
+	for s, list := range f.hostTags[host] {
+		if r.URL.Query().Get("source") == "" || s == source {
+			all = append(all, list...)
+		}
+	}
+	fakeJSON(w, map[string]interface{}{"host": host, "tags": all})
+}
+
+// serveObjects handles the plain CRUD endpoints shared by most object types.
+// onCreate and onDelete, when set, replace the default behaviour.
+func (f *fakeAPI) serveObjects(w http.ResponseWriter, r *http.Request, path []string, body map[string]interface{},
+	objects map[int]map[string]interface{}, onCreate func(map[string]interface{}), onDelete func(int)) {
+	if len(path) == 0 {
+		switch r.Method {
+		case "GET":
+			list := []interface{}{}
+			for _, id := range sortedIDs(objects) {
+				list = append(list, objects[id])
+			}
+			fakeJSON(w, list)
+		case "POST":
+			if onCreate != nil {
+				onCreate(body)
+			}
+			fakeJSON(w, f.store(objects, body))
+		default:
+			fakeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+		return
+	}
+
+	id := fakeID(path[0])
+	o := objects[id]
+	if o == nil || len(path) > 1 {
+		fakeError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		fakeJSON(w, o)
+	case "PUT":
+		for k, v := range body {
+			o[k] = v
+		}
+		o["id"] = id
+		fakeJSON(w, o)
+	case "DELETE":
+		if onDelete != nil {
+			onDelete(id)
+		} else {
+			delete(objects, id)
+		}
+		fakeJSON(w, map[string]interface{}{"deleted_id": id})
+	default:
+		fakeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// store saves o under a new ID and returns it.
+func (f *fakeAPI) store(objects map[int]map[string]interface{}, o map[string]interface{}) map[string]interface{} {
+	if o == nil {
+		o = make(map[string]interface{})
+	}
+	o["id"] = f.nextID
+	objects[f.nextID] = o
+	f.nextID++
+	return o
+}
+
+// fakeMute sets or clears options.silenced of monitor m, from a body with an
+// optional scope and end.
+func fakeMute(m map[string]interface{}, mute bool, body map[string]interface{}) {
+	options, _ := m["options"].(map[string]interface{})
+	if options == nil {
+		options = make(map[string]interface{})
+		m["options"] = options
+	}
+	silenced, _ := options["silenced"].(map[string]interface{})
+	if silenced == nil {
+		silenced = make(map[string]interface{})
+	}
+
+	scope, _ := body["scope"].(string)
+	if scope == "" {
+		scope = "*"
+	}
+	switch {
+	case mute:
+		silenced[scope] = body["end"]
+	case body["all_scopes"] == true:
+		silenced = make(map[string]interface{})
+	default:
+		delete(silenced, scope)
+	}
+	options["silenced"] = silenced
+}
+
+func fakeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func fakeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{"errors": []string{message}})
+}
+
+func fakeID(s string) int {
+	id, _ := strconv.Atoi(s)
+	return id
+}
+
+func sortedIDs(objects map[int]map[string]interface{}) []int {
+	ids := make([]int, 0, len(objects))
+	for id := range objects {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+func copyObject(o map[string]interface{}) map[string]interface{} {
+	if o == nil {
+		return nil
+	}
+	b, _ := json.Marshal(o)
+	var c map[string]interface{}
+	json.Unmarshal(b, &c)
+	return c
+}
@@ -0,0 +1,107 @@
+package datadog
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/zorkian/go-datadog-api"
+)
+
+// loggingTransport is an http.RoundTripper that logs every Datadog API call
+// at DEBUG level: method, path, status, latency and the JSON bodies. Keys
+// are redacted and bodies are cut off after bodyLimit bytes.
+type loggingTransport struct {
+	transport http.RoundTripper
+	bodyLimit int
+	secrets   []string
+}
+
+func newLoggingTransport(t http.RoundTripper, bodyLimit int, secrets ...string) *loggingTransport {
+	return &loggingTransport{
+		transport: t,
+		bodyLimit: bodyLimit,
+		secrets:   secrets,
+	}
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !isDebugLogging() {
+		return t.transport.RoundTrip(req)
+	}
+
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		if reqBody, err = ioutil.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+		req.Body = ioutil.NopCloser(bytes.NewReader(reqBody))
+	}
+	path := datadog.RedactUrl(req.URL.RequestURI())
+	log.Printf("[DEBUG] Datadog API request: %s %s %s", req.Method, path, t.formatBody(reqBody))
+
+	start := time.Now()
+	resp, err := t.transport.RoundTrip(req)
+	latency := time.Since(start)
+	if err != nil {
+		log.Printf("[DEBUG] Datadog API error: %s %s after %s: %s", req.Method, path, latency, t.redact(err.Error()))
+		return resp, err
+	}
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("[DEBUG] Datadog API response: %s %s %s in %s %s",
+		req.Method, path, resp.Status, latency, t.formatBody(respBody))
+
+	return resp, nil
+}
+
+// formatBody returns body fit for the log: redacted and truncated.
+func (t *loggingTransport) formatBody(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	s := t.redact(string(body))
+	if t.bodyLimit > 0 && len(s) > t.bodyLimit {
+		// Cut before the rune the limit falls in, so the log stays valid
+		// UTF-8.
+		n := t.bodyLimit
+		for n > 0 && !utf8.RuneStart(s[n]) {
+			n--
+		}
+		return fmt.Sprintf("%s... (truncated, %d bytes)", s[:n], len(s))
+	}
+	return s
+}
+
+// redact replaces every secret in s.
+func (t *loggingTransport) redact(s string) string {
+	for _, secret := range t.secrets {
+		if secret != "" {
+			s = strings.Replace(s, secret, "<redacted>", -1)
+		}
+	}
+	return s
+}
+
+// isDebugLogging tells whether Terraform is logging at DEBUG level or higher,
+// so we only read and format bodies when they will end up in the log.
+func isDebugLogging() bool {
+	switch strings.ToUpper(os.Getenv("TF_LOG")) {
+	case "TRACE", "DEBUG":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+package datadog
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/zorkian/go-datadog-api"
+)
+
+func TestLoggingTransport(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id": 1, "name": "` + strings.Repeat("x", 100) + `"}`))
+	}))
+	defer ts.Close()
+
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+	defer os.Setenv("TF_LOG", os.Getenv("TF_LOG"))
+
+	c := Config{APIKey: testSecretAPIKey, APPKey: testSecretAPPKey, APIURL: ts.URL, LogBodyLimit: 64}
+	client, err := c.Client()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// The message carries a key, to make sure bodies are redacted too.
+	m := &datadog.Monitor{Name: "foo", Message: "leaked " + testSecretAPIKey}
+
+	os.Setenv("TF_LOG", "")
+	if _, err := client.CreateMonitor(m); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if strings.Contains(buf.String(), "Datadog API") {
+		t.Fatalf("Expected no API logging without TF_LOG, got:\n%s", buf.String())
+	}
+
+	os.Setenv("TF_LOG", "DEBUG")
+	created, err := client.CreateMonitor(m)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if created.Id != 1 {
+		t.Fatalf("Expected the response body to still be readable, got monitor %#v", created)
+	}
+
+	logged := buf.String()
+	for _, expected := range []string{
+		"[DEBUG] Datadog API request: POST /api/v1/monitor",
+		`"message":"leaked <redacted>"`,
+		"[DEBUG] Datadog API response: POST /api/v1/monitor 200 OK in ",
+		"... (truncated, 121 bytes)",
+	} {
+		if !strings.Contains(logged, expected) {
+			t.Fatalf("Expected log output to contain %q, got:\n%s", expected, logged)
+		}
+	}
+	for _, secret := range []string{testSecretAPIKey, testSecretAPPKey} {
+		if strings.Contains(logged, secret) {
+			t.Fatalf("Key %q found in log output:\n%s", secret, logged)
+		}
+	}
+}
+
+func TestLoggingTransport_TruncatesAtRunes(t *testing.T) {
+	lt := newLoggingTransport(http.DefaultTransport, 5)
+	for body, expected := range map[string]string{
+		"abcdefgh": "abcde... (truncated, 8 bytes)",
+		"abcdéfg":  "abcd... (truncated, 8 bytes)",
+		"ab€def":   "ab€... (truncated, 8 bytes)",
+		"abc":      "abc",
+	} {
+		if v := lt.formatBody([]byte(body)); v != expected {
+			t.Fatalf("formatBody(%q): expected %q, got %q", body, expected, v)
+		}
+		if !utf8.ValidString(lt.formatBody([]byte(body))) {
+			t.Fatalf("formatBody(%q) is not valid UTF-8", body)
+		}
+	}
+}
@@ -1,10 +1,14 @@
 package datadog
 
 import (
+	"fmt"
 	"log"
+	"net/url"
+	"time"
 
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
+	"github.com/zorkian/go-datadog-api"
 )
 
 // Provider returns a terraform.ResourceProvider.
@@ -21,13 +25,79 @@ func Provider() terraform.ResourceProvider {
 				Required:    true,
 				DefaultFunc: schema.EnvDefaultFunc("DATADOG_APP_KEY", nil),
 			},
+			"api_url": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.MultiEnvDefaultFunc([]string{"DATADOG_API_URL", "DATADOG_HOST"}, datadog.DefaultBaseUrl),
+				ValidateFunc: validateAPIURL,
+			},
+			"max_retries": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      datadog.DefaultMaxRetries,
+				ValidateFunc: validateNonNegativeInt,
+			},
+			"max_retry_wait": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      int(datadog.DefaultMaxRetryWait / time.Second),
+				ValidateFunc: validateNonNegativeInt,
+			},
+			"retry_timeout": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      int(datadog.DefaultRetryTimeout / time.Second),
+				ValidateFunc: validateNonNegativeInt,
+			},
+			"validate": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"proxy_url": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateProxyURL,
+			},
+			"ca_bundle": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"tls_min_version": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "1.2",
+				ValidateFunc: validateTLSVersion,
+			},
+			"request_timeout": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      30,
+				ValidateFunc: validateNonNegativeInt,
+			},
+			"default_tags": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"log_body_limit": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      4096,
+				ValidateFunc: validateNonNegativeInt,
+			},
 		},
 
 		ResourcesMap: map[string]*schema.Resource{
+			"datadog_downtime":      resourceDatadogDowntime(),
 			"datadog_monitor":       resourceDatadogMonitor(),
+			"datadog_monitor_mute":  resourceDatadogMonitorMute(),
 			"datadog_service_check": resourceDatadogServiceCheck(),
 			"datadog_metric_alert":  resourceDatadogMetricAlert(),
 			"datadog_outlier_alert": resourceDatadogOutlierAlert(),
+			"datadog_timeboard":     resourceDatadogTimeboard(),
+			"datadog_screenboard":   resourceDatadogScreenboard(),
+			"datadog_user":          resourceDatadogUser(),
 		},
 
 		ConfigureFunc: providerConfigure,
@@ -40,8 +110,82 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 	config := Config{
 		APIKey: d.Get("api_key").(string),
 		APPKey: d.Get("app_key").(string),
+		APIURL: d.Get("api_url").(string),
+
+		MaxRetries:   d.Get("max_retries").(int),
+		MaxRetryWait: time.Duration(d.Get("max_retry_wait").(int)) * time.Second,
+		RetryTimeout: time.Duration(d.Get("retry_timeout").(int)) * time.Second,
+
+		Validate: d.Get("validate").(bool),
+
+		ProxyURL:      d.Get("proxy_url").(string),
+		CABundle:      d.Get("ca_bundle").(string),
+		TLSMinVersion: d.Get("tls_min_version").(string),
+
+		RequestTimeout: time.Duration(d.Get("request_timeout").(int)) * time.Second,
+
+		LogBodyLimit: d.Get("log_body_limit").(int),
 	}
 
 	log.Println("[INFO] Initializing Datadog client")
-	return config.Client()
+	client, err := config.Client()
+	if err != nil {
+		return nil, err
+	}
+
+	meta := &providerMeta{client: client}
+	for _, v := range d.Get("default_tags").([]interface{}) {
+		meta.defaultTags = append(meta.defaultTags, v.(string))
+	}
+
+	return meta, nil
+}
+
+// validateAPIURL checks api_url is an absolute http(s) URL without a path, the
+// client adds "/api" itself.
+func validateAPIURL(v interface{}, k string) (ws []string, es []error) {
+	u, err := url.Parse(v.(string))
+	if err != nil {
+		es = append(es, fmt.Errorf("%q is not a valid URL: %s", k, err))
+		return
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		es = append(es, fmt.Errorf("%q must use http or https, got %q", k, u.Scheme))
+	}
+	if u.Host == "" {
+		es = append(es, fmt.Errorf("%q must include a host, got %q", k, v))
+	}
+	if u.Path != "" && u.Path != "/" {
+		es = append(es, fmt.Errorf("%q must not include a path, got %q", k, u.Path))
+	}
+	return
+}
+
+func validateNonNegativeInt(v interface{}, k string) (ws []string, es []error) {
+	if v.(int) < 0 {
+		es = append(es, fmt.Errorf("%q must not be negative, got %d", k, v))
+	}
+	return
+}
+
+func validateProxyURL(v interface{}, k string) (ws []string, es []error) {
+	u, err := url.Parse(v.(string))
+	if err != nil {
+		es = append(es, fmt.Errorf("%q is not a valid URL: %s", k, err))
+		return
+	}
+	if u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "socks5" {
+		es = append(es, fmt.Errorf("%q must use http, https or socks5, got %q", k, u.Scheme))
+	}
+	if u.Host == "" {
+		es = append(es, fmt.Errorf("%q must include a host, got %q", k, v))
+	}
+	return
+}
+
+func validateTLSVersion(v interface{}, k string) (ws []string, es []error) {
+	if _, ok := tlsVersions[v.(string)]; !ok {
+		es = append(es, fmt.Errorf("%q must be one of 1.0, 1.1, 1.2 or 1.3, got %q", k, v))
+	}
+	return
 }
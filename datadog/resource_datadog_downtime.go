@@ -0,0 +1,288 @@
+package datadog
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/zorkian/go-datadog-api"
+)
+
+// resourceDatadogDowntime is a Datadog downtime resource
+func resourceDatadogDowntime() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceDatadogDowntimeCreate,
+		Read:   resourceDatadogDowntimeRead,
+		Update: resourceDatadogDowntimeUpdate,
+		Delete: resourceDatadogDowntimeDelete,
+
+		Schema: map[string]*schema.Schema{
+			"scope": &schema.Schema{
+				Type:     schema.TypeList,
+				Required: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"monitor_id": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+				ForceNew: true,
+			},
+			"message": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"start": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validateRFC3339,
+			},
+			"end": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRFC3339,
+			},
+			// Datadog cannot remove a recurrence from a downtime, so any
+			// change to it replaces the downtime.
+			"recurrence": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				ForceNew: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"type": &schema.Schema{
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateRecurrenceType,
+						},
+						"period": &schema.Schema{
+							Type:     schema.TypeInt,
+							Required: true,
+						},
+						"week_days": &schema.Schema{
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"until_date": &schema.Schema{
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validateRFC3339,
+						},
+						"until_occurrences": &schema.Schema{
+							Type:     schema.TypeInt,
+							Optional: true,
+						},
+					},
+				},
+			},
+
+			// Read only, set by Datadog
+			"active": &schema.Schema{
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// recurrenceTypes are the units a downtime can recur in.
+var recurrenceTypes = map[string]bool{"days": true, "weeks": true, "months": true, "years": true}
+
+func validateRecurrenceType(v interface{}, k string) (ws []string, es []error) {
+	if !recurrenceTypes[v.(string)] {
+		es = append(es, fmt.Errorf("%q must be one of days, weeks, months or years, got %q", k, v))
+	}
+	return
+}
+
+// weekDays are the days a weekly downtime can recur on.
+var weekDays = map[string]bool{
+	"Mon": true, "Tue": true, "Wed": true, "Thu": true, "Fri": true, "Sat": true, "Sun": true}
+
+// buildDowntimeStruct returns the downtime of d. Like checkMonitor, it checks
+// what spans several fields before anything is sent to Datadog.
+func buildDowntimeStruct(d *schema.ResourceData) (*datadog.Downtime, error) {
+	start, err := getTime(d, "start")
+	if err != nil {
+		return nil, err
+	}
+	end, err := getTime(d, "end")
+	if err != nil {
+		return nil, err
+	}
+	if start != 0 && end != 0 && end <= start {
+		return nil, fmt.Errorf("downtime end %s must be after its start %s", d.Get("end"), d.Get("start"))
+	}
+
+	dt := datadog.Downtime{
+		Scope:     getStringList(d, "scope"),
+		MonitorId: d.Get("monitor_id").(int),
+		Message:   d.Get("message").(string),
+		Start:     int(start),
+		End:       int(end),
+	}
+
+	switch n := d.Get("recurrence.#").(int); {
+	case n > 1:
+		return nil, fmt.Errorf("a downtime takes one recurrence block, got %d", n)
+	case n == 1:
+		r, err := buildRecurrence(d)
+		if err != nil {
+			return nil, err
+		}
+		dt.Recurrence = r
+	}
+
+	return &dt, nil
+}
+
+func buildRecurrence(d *schema.ResourceData) (*datadog.Recurrence, error) {
+	r := datadog.Recurrence{
+		Type:             d.Get("recurrence.0.type").(string),
+		Period:           d.Get("recurrence.0.period").(int),
+		WeekDays:         getStringList(d, "recurrence.0.week_days"),
+		UntilOccurrences: d.Get("recurrence.0.until_occurrences").(int),
+	}
+
+	if r.Period < 1 {
+		return nil, fmt.Errorf("recurrence period must be at least 1, got %d", r.Period)
+	}
+	if len(r.WeekDays) > 0 && r.Type != "weeks" {
+		return nil, fmt.Errorf("recurrence week_days only apply to weekly downtimes, type is %q", r.Type)
+	}
+	for _, day := range r.WeekDays {
+		if !weekDays[day] {
+			return nil, fmt.Errorf("recurrence week_days must be Mon, Tue, Wed, Thu, Fri, Sat or Sun, got %q", day)
+		}
+	}
+
+	until, err := getTime(d, "recurrence.0.until_date")
+	if err != nil {
+		return nil, err
+	}
+	if until != 0 && r.UntilOccurrences != 0 {
+		return nil, fmt.Errorf("recurrence takes until_date or until_occurrences, not both")
+	}
+	r.UntilDate = int(until)
+
+	return &r, nil
+}
+
+func resourceDatadogDowntimeCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*providerMeta).client
+
+	dt, err := buildDowntimeStruct(d)
+	if err != nil {
+		return err
+	}
+
+	created, err := client.CreateDowntime(dt)
+	if err != nil {
+		return fmt.Errorf("error creating downtime: %s", err.Error())
+	}
+	d.SetId(strconv.Itoa(created.Id))
+
+	return resourceDatadogDowntimeRead(d, meta)
+}
+
+// resourceDatadogDowntimeRead reads a downtime back. Datadog keeps canceled
+// downtimes, so those are removed from state like deleted ones. A downtime
+// that ended is kept, with active false, as it is still what was configured.
+func resourceDatadogDowntimeRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*providerMeta).client
+
+	i, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return err
+	}
+
+	dt, err := client.GetDowntime(i)
+	if datadog.IsNotFound(err) {
+		log.Printf("[WARN] downtime %d not found, removing from state", i)
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error reading downtime %d: %s", i, err)
+	}
+	if dt.Canceled != 0 {
+		log.Printf("[WARN] downtime %d was canceled, removing from state", i)
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("scope", dt.Scope)
+	d.Set("monitor_id", dt.MonitorId)
+	d.Set("message", dt.Message)
+	d.Set("active", dt.Active)
+
+	// Datadog moves start and end to the current occurrence of a recurring
+	// downtime. Later occurrences are not a change.
+	oldStart, _ := getTime(d, "start")
+	if dt.Recurrence == nil || oldStart == 0 || int64(dt.Start) < oldStart {
+		setTime(d, "start", int64(dt.Start))
+		setTime(d, "end", int64(dt.End))
+	}
+
+	var recurrence []map[string]interface{}
+	if r := dt.Recurrence; r != nil {
+		until := ""
+		if r.UntilDate != 0 {
+			until = d.Get("recurrence.0.until_date").(string)
+			if t, err := getTime(d, "recurrence.0.until_date"); err != nil || t != int64(r.UntilDate) {
+				until = formatTime(int64(r.UntilDate))
+			}
+		}
+		recurrence = append(recurrence, map[string]interface{}{
+			"type":              r.Type,
+			"period":            r.Period,
+			"week_days":         r.WeekDays,
+			"until_date":        until,
+			"until_occurrences": r.UntilOccurrences,
+		})
+	}
+	d.Set("recurrence", recurrence)
+
+	return nil
+}
+
+func resourceDatadogDowntimeUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*providerMeta).client
+
+	i, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return err
+	}
+
+	dt, err := buildDowntimeStruct(d)
+	if err != nil {
+		return err
+	}
+	dt.Id = i
+
+	if err := client.UpdateDowntime(dt); err != nil {
+		return fmt.Errorf("error updating downtime %d: %s", i, err.Error())
+	}
+
+	return resourceDatadogDowntimeRead(d, meta)
+}
+
+// resourceDatadogDowntimeDelete cancels a downtime, which is what a delete
+// does in Datadog.
+func resourceDatadogDowntimeDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*providerMeta).client
+
+	i, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return err
+	}
+
+	// A downtime that is already gone needs no canceling.
+	if err = client.DeleteDowntime(i); err != nil && !datadog.IsNotFound(err) {
+		return fmt.Errorf("error deleting downtime %d: %s", i, err.Error())
+	}
+
+	return nil
+}
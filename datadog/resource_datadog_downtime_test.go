@@ -0,0 +1,291 @@
+package datadog
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/zorkian/go-datadog-api"
+)
+
+func TestAccDatadogDowntime_Basic(t *testing.T) {
+	var id string
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDatadogDowntimeDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckDatadogDowntimeConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDatadogDowntimeExists("datadog_downtime.foo"),
+					resource.TestCheckResourceAttr(
+						"datadog_downtime.foo", "scope.#", "1"),
+					resource.TestCheckResourceAttr(
+						"datadog_downtime.foo", "scope.0", "env:staging"),
+					resource.TestCheckResourceAttr(
+						"datadog_downtime.foo", "message", "upgrading the database"),
+					resource.TestCheckResourceAttr(
+						"datadog_downtime.foo", "start", "2030-01-02T15:00:00Z"),
+					resource.TestCheckResourceAttr(
+						"datadog_downtime.foo", "end", "2030-01-02T17:00:00Z"),
+					resource.TestCheckResourceAttr(
+						"datadog_downtime.foo", "active", "true"),
+					testAccCheckDatadogDowntimeID("datadog_downtime.foo", &id, false),
+				),
+			},
+			resource.TestStep{
+				// A start in another time zone is the same time.
+				Config: testAccCheckDatadogDowntimeConfigUpdated,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"datadog_downtime.foo", "message", "upgrading the database, again"),
+					resource.TestCheckResourceAttr(
+						"datadog_downtime.foo", "start", "2030-01-02T16:00:00+01:00"),
+					resource.TestCheckResourceAttr(
+						"datadog_downtime.foo", "end", "2030-01-02T18:00:00Z"),
+					testAccCheckDatadogDowntimeID("datadog_downtime.foo", &id, false),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDatadogDowntime_Recurrence(t *testing.T) {
+	var id string
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDatadogDowntimeDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckDatadogDowntimeConfigRecurrence,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDatadogDowntimeExists("datadog_downtime.weekly"),
+					resource.TestCheckResourceAttr(
+						"datadog_downtime.weekly", "recurrence.#", "1"),
+					resource.TestCheckResourceAttr(
+						"datadog_downtime.weekly", "recurrence.0.type", "weeks"),
+					resource.TestCheckResourceAttr(
+						"datadog_downtime.weekly", "recurrence.0.period", "1"),
+					resource.TestCheckResourceAttr(
+						"datadog_downtime.weekly", "recurrence.0.week_days.#", "2"),
+					resource.TestCheckResourceAttr(
+						"datadog_downtime.weekly", "recurrence.0.week_days.1", "Wed"),
+					resource.TestCheckResourceAttr(
+						"datadog_downtime.weekly", "recurrence.0.until_date", "2031-01-01T00:00:00Z"),
+					testAccCheckDatadogDowntimeID("datadog_downtime.weekly", &id, false),
+				),
+			},
+			resource.TestStep{
+				// Datadog moving on to the next occurrence is not a change.
+				PreConfig: func() {
+					if testAccFake != nil {
+						i, _ := strconv.Atoi(id)
+						week := int64(7 * 24 * 60 * 60)
+						testAccFake.UpdateDowntime(i, map[string]interface{}{
+							"start": 1893596400 + week, "end": 1893600000 + week})
+					}
+				},
+				Config: testAccCheckDatadogDowntimeConfigRecurrence,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"datadog_downtime.weekly", "start", "2030-01-02T15:00:00Z"),
+					testAccCheckDatadogDowntimeID("datadog_downtime.weekly", &id, false),
+					func(s *terraform.State) error {
+						if testAccFake == nil {
+							return nil
+						}
+						i, _ := strconv.Atoi(id)
+						if start, _ := testAccFake.Downtime(i)["start"].(float64); start != 1894201200 {
+							return fmt.Errorf("Expected the downtime not to be moved back, start is %.0f", start)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccDatadogDowntime_EndedOrCanceled(t *testing.T) {
+	if testAccFake == nil {
+		t.Skip("Ending and canceling downtimes needs the fake API, TF_ACC is set")
+	}
+
+	var id string
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDatadogDowntimeDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckDatadogDowntimeConfig,
+				Check:  testAccCheckDatadogDowntimeID("datadog_downtime.foo", &id, false),
+			},
+			resource.TestStep{
+				// An ended downtime is kept as it is.
+				PreConfig: func() {
+					i, _ := strconv.Atoi(id)
+					testAccFake.UpdateDowntime(i, map[string]interface{}{"active": false})
+				},
+				Config: testAccCheckDatadogDowntimeConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"datadog_downtime.foo", "active", "false"),
+					testAccCheckDatadogDowntimeID("datadog_downtime.foo", &id, false),
+				),
+			},
+			resource.TestStep{
+				// A canceled downtime is created again.
+				PreConfig: func() {
+					i, _ := strconv.Atoi(id)
+					testAccFake.CancelDowntime(i)
+				},
+				Config: testAccCheckDatadogDowntimeConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"datadog_downtime.foo", "active", "true"),
+					testAccCheckDatadogDowntimeID("datadog_downtime.foo", &id, true),
+				),
+			},
+		},
+	})
+}
+
+func TestBuildDowntimeStruct(t *testing.T) {
+	cases := []struct {
+		Name       string
+		Start      string
+		End        string
+		Recurrence map[string]interface{}
+		Err        string
+	}{
+		{"one-off", "2030-01-02T15:00:00Z", "2030-01-02T17:00:00Z", nil, ""},
+		{"no end", "", "", nil, ""},
+		{"end before start", "2030-01-02T15:00:00Z", "2030-01-02T15:00:00Z", nil,
+			"downtime end 2030-01-02T15:00:00Z must be after its start 2030-01-02T15:00:00Z"},
+		{"weekly", "2030-01-02T15:00:00Z", "", map[string]interface{}{
+			"type": "weeks", "period": 2, "week_days": []string{"Mon", "Sun"}, "until_occurrences": 4}, ""},
+		{"no period", "", "", map[string]interface{}{"type": "days"},
+			"recurrence period must be at least 1, got 0"},
+		{"week days of a monthly downtime", "", "", map[string]interface{}{
+			"type": "months", "period": 1, "week_days": []string{"Mon"}},
+			`recurrence week_days only apply to weekly downtimes, type is "months"`},
+		{"unknown week day", "", "", map[string]interface{}{
+			"type": "weeks", "period": 1, "week_days": []string{"Monday"}},
+			`recurrence week_days must be Mon, Tue, Wed, Thu, Fri, Sat or Sun, got "Monday"`},
+		{"until date and occurrences", "", "", map[string]interface{}{
+			"type": "days", "period": 1, "until_date": "2031-01-01T00:00:00Z", "until_occurrences": 3},
+			"recurrence takes until_date or until_occurrences, not both"},
+	}
+
+	for _, tc := range cases {
+		d := resourceDatadogDowntime().TestResourceData()
+		d.Set("scope", []string{"*"})
+		d.Set("start", tc.Start)
+		d.Set("end", tc.End)
+		if tc.Recurrence != nil {
+			d.Set("recurrence", []map[string]interface{}{tc.Recurrence})
+		}
+
+		dt, err := buildDowntimeStruct(d)
+		if tc.Err != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.Err) {
+				t.Fatalf("%s: expected error %q, got %v", tc.Name, tc.Err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %s", tc.Name, err)
+		}
+		if (tc.Recurrence == nil) != (dt.Recurrence == nil) {
+			t.Fatalf("%s: expected recurrence %v, got %#v", tc.Name, tc.Recurrence, dt.Recurrence)
+		}
+	}
+}
+
+// testAccCheckDatadogDowntimeID stores the ID of downtime n in id, checking
+// it changed from the stored one when changed is set, and did not otherwise.
+func testAccCheckDatadogDowntimeID(n string, id *string, changed bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		current := s.RootModule().Resources[n].Primary.ID
+		if *id != "" && (current != *id) != changed {
+			return fmt.Errorf("Expected the ID of %s to change from %s: %t, got %s", n, *id, changed, current)
+		}
+		*id = current
+		return nil
+	}
+}
+
+func testAccCheckDatadogDowntimeExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*providerMeta).client
+		i, _ := strconv.Atoi(s.RootModule().Resources[n].Primary.ID)
+		if _, err := client.GetDowntime(i); err != nil {
+			return fmt.Errorf("Received an error retrieving downtime %s", err)
+		}
+		return nil
+	}
+}
+
+// testAccCheckDatadogDowntimeDestroy checks the downtimes were canceled, as
+// Datadog keeps them after a delete.
+func testAccCheckDatadogDowntimeDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*providerMeta).client
+
+	for _, r := range s.RootModule().Resources {
+		if r.Type != "datadog_downtime" {
+			continue
+		}
+		i, _ := strconv.Atoi(r.Primary.ID)
+		dt, err := client.GetDowntime(i)
+		if datadog.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("Received an error retrieving downtime %s", err)
+		}
+		if dt.Canceled == 0 {
+			return fmt.Errorf("Downtime %d was not canceled", i)
+		}
+	}
+	return nil
+}
+
+const testAccCheckDatadogDowntimeConfig = `
+resource "datadog_downtime" "foo" {
+  scope = ["env:staging"]
+  message = "upgrading the database"
+  start = "2030-01-02T15:00:00Z"
+  end = "2030-01-02T17:00:00Z"
+}
+`
+
+const testAccCheckDatadogDowntimeConfigUpdated = `
+resource "datadog_downtime" "foo" {
+  scope = ["env:staging"]
+  message = "upgrading the database, again"
+  start = "2030-01-02T16:00:00+01:00"
+  end = "2030-01-02T18:00:00Z"
+}
+`
+
+const testAccCheckDatadogDowntimeConfigRecurrence = `
+resource "datadog_downtime" "weekly" {
+  scope = ["env:staging", "role:db"]
+  message = "weekly maintenance"
+  start = "2030-01-02T15:00:00Z"
+  end = "2030-01-02T16:00:00Z"
+
+  recurrence {
+	type = "weeks"
+	period = 1
+	week_days = ["Mon", "Wed"]
+	until_date = "2031-01-01T00:00:00Z"
+  }
+}
+`
@@ -1,11 +1,11 @@
 package datadog
 
 import (
-	"bytes"
 	"fmt"
 	"log"
 
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/ojongerius/terraform-provider-datadog/query"
 	"github.com/zorkian/go-datadog-api"
 )
 
@@ -13,11 +13,14 @@ import (
 func resourceDatadogMetricAlert() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceDatadogMetricAlertCreate,
-		Read:   resourceDatadogGenericRead,
+		Read:   resourceDatadogMetricAlertRead,
 		Update: resourceDatadogMetricAlertUpdate,
-		Delete: resourceDatadogGenericDelete,
+		Delete: resourceDatadogLegacyMonitorDelete,
 		Exists: resourceDatadogGenericExists,
 
+		SchemaVersion: monitorSchemaVersion,
+		MigrateState:  migrateMonitorState,
+
 		Schema: map[string]*schema.Schema{
 			"name": &schema.Schema{
 				Type:     schema.TypeString,
@@ -55,8 +58,9 @@ func resourceDatadogMetricAlert() *schema.Resource {
 				ConflictsWith: []string{"query"},
 			},
 			"operator": &schema.Schema{
-				Type:     schema.TypeString,
-				Required: true,
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateOperator,
 			},
 			"message": &schema.Schema{
 				Type:     schema.TypeString,
@@ -90,100 +94,111 @@ func resourceDatadogMetricAlert() *schema.Resource {
 				Optional: true,
 				Default:  0,
 			},
+
+			// Read only, set when a pre 0.0.4 ID is migrated
+			"legacy_monitor_ids": legacyMonitorIDsSchema(),
 		},
 	}
 }
 
-// buildMonitorStruct returns a monitor struct
-func buildMetricAlertStruct(d *schema.ResourceData) *datadog.Monitor {
-	name := d.Get("name").(string)
-	message := d.Get("message").(string)
-	timeAggr := d.Get("time_aggr").(string)
-	timeWindow := d.Get("time_window").(string)
-	spaceAggr := d.Get("space_aggr").(string)
-	metric := d.Get("metric").(string)
-	query := d.Get("query").(string)
-
-	// Tags are are no separate resource/gettable, so some trickery is needed
-	var buffer bytes.Buffer
-	if raw, ok := d.GetOk("tags"); ok {
-		list := raw.([]interface{})
-		length := (len(list) - 1)
-		for i, v := range list {
-			buffer.WriteString(fmt.Sprintf("%s", v))
-			if i != length {
-				buffer.WriteString(",")
-			}
+// buildMetricAlertQuery returns the query described by d.
+func buildMetricAlertQuery(d *schema.ResourceData) query.Query {
+	threshold, _ := getThresholds(d)
+	operator := d.Get("operator").(string)
 
-		}
+	if q := d.Get("query").(string); q != "" {
+		return &query.Comparison{Expr: q, Operator: operator, Threshold: threshold}
 	}
 
-	tagsParsed := buffer.String()
-
-	// Keys are used for multi alerts
-	var b bytes.Buffer
-	if raw, ok := d.GetOk("keys"); ok {
-		list := raw.([]interface{})
-		b.WriteString("by {")
-		length := (len(list) - 1)
-		for i, v := range list {
-			b.WriteString(fmt.Sprintf("%s", v))
-			if i != length {
-				b.WriteString(",")
-			}
-
-		}
-		b.WriteString("}")
+	return &query.Metric{
+		TimeAggr:   d.Get("time_aggr").(string),
+		TimeWindow: d.Get("time_window").(string),
+		SpaceAggr:  d.Get("space_aggr").(string),
+		Metric:     d.Get("metric").(string),
+		Scope:      getStringList(d, "tags"),
+		By:         getStringList(d, "keys"),
+		Operator:   operator,
+		Threshold:  threshold,
 	}
+}
 
-	keys := b.String()
-
-	threshold, thresholds := getThresholds(d)
-
-	operator := d.Get("operator").(string)
-
-	var q string
-
-	if query == "" {
-		q = fmt.Sprintf("%s(%s):%s:%s{%s} %s %s %s", timeAggr,
-			timeWindow,
-			spaceAggr,
-			metric,
-			tagsParsed,
-			keys,
-			operator,
-			threshold)
-	} else {
-		q = fmt.Sprintf("%s %s %s", query, operator, threshold)
-	}
+// buildMetricAlertStruct returns a monitor struct
+func buildMetricAlertStruct(d *schema.ResourceData) *datadog.Monitor {
+	q := buildMetricAlertQuery(d).String()
+	log.Printf("[DEBUG] submitting query: %s", q)
 
-	log.Print(fmt.Sprintf("[DEBUG] submitting query: %s", q))
+	_, thresholds := getThresholds(d)
 
-	o := datadog.Options{
-		NotifyNoData:     d.Get("notify_no_data").(bool),
-		NoDataTimeframe:  d.Get("no_data_timeframe").(int),
-		RenotifyInterval: d.Get("renotify_interval").(int),
-		Thresholds:       thresholds,
-	}
+	o := buildMonitorOptions(d)
+	o.Thresholds = thresholds
 
 	m := datadog.Monitor{
 		Type:    "metric alert",
 		Query:   q,
-		Name:    name,
-		Message: message,
+		Name:    d.Get("name").(string),
+		Message: d.Get("message").(string),
 		Options: o,
 	}
 
 	return &m
 }
 
-// resourceDatadogMetricAlertCreate creates a monitor.
+// resourceDatadogMetricAlertCreate creates a monitor. The order of the
+// thresholds depends on the operator, so it is only checked here and in
+// Update, not while planning.
 func resourceDatadogMetricAlertCreate(d *schema.ResourceData, meta interface{}) error {
+	_, thresholds := getThresholds(d)
+	if err := checkThresholdOrder(d.Get("operator").(string), thresholds); err != nil {
+		return err
+	}
 
 	m := buildMetricAlertStruct(d)
 	if err := monitorCreator(d, meta, m); err != nil {
 		return err
 	}
+	readLegacyMonitorIDs(d)
+
+	return nil
+}
+
+// resourceDatadogMetricAlertRead syncs the state with the monitor in Datadog.
+func resourceDatadogMetricAlertRead(d *schema.ResourceData, meta interface{}) error {
+	m, err := readMonitor(d, meta)
+	if err != nil || m == nil {
+		return err
+	}
+
+	// The fields are only set when the query changed, so formatting that the
+	// canonical form of the query does away with is not a change.
+	if d.Get("query").(string) != "" {
+		q, err := query.ParseComparison(m.Query)
+		if err != nil {
+			return fmt.Errorf("monitor %d: %s", m.Id, err)
+		}
+		if !query.Equal(q, buildMetricAlertQuery(d)) {
+			d.Set("query", q.Expr)
+			d.Set("operator", q.Operator)
+		}
+	} else {
+		q, err := query.ParseMetric(m.Query)
+		if err != nil {
+			return fmt.Errorf("monitor %d has a query that cannot be expressed with metric, tags, keys, "+
+				"time_aggr, time_window and space_aggr, set query instead: %s", m.Id, err)
+		}
+		if !query.Equal(q, buildMetricAlertQuery(d)) {
+			d.Set("time_aggr", q.TimeAggr)
+			d.Set("time_window", q.TimeWindow)
+			d.Set("space_aggr", q.SpaceAggr)
+			d.Set("metric", q.Metric)
+			readScope(d, q.Scope)
+			d.Set("keys", q.By)
+			d.Set("operator", q.Operator)
+		}
+	}
+
+	readMonitorOptions(d, resourceDatadogMetricAlert().Schema, m)
+	readLegacyMonitorIDs(d)
+	readThresholds(d, m.Options.Thresholds)
 
 	return nil
 }
@@ -192,8 +207,13 @@ func resourceDatadogMetricAlertCreate(d *schema.ResourceData, meta interface{})
 func resourceDatadogMetricAlertUpdate(d *schema.ResourceData, meta interface{}) error {
 	log.Printf("[DEBUG] running update.")
 
+	_, thresholds := getThresholds(d)
+	if err := checkThresholdOrder(d.Get("operator").(string), thresholds); err != nil {
+		return err
+	}
+
 	m := buildMetricAlertStruct(d)
-	if err := monitorUpdater(d, meta, m); err != nil {
+	if err := monitorUpdater(d, meta, m, nil); err != nil {
 		return err
 	}
 
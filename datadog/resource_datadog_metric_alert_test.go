@@ -1,11 +1,12 @@
 package datadog
 
 import (
+	"fmt"
+	"strconv"
 	"testing"
 
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/terraform"
-	"github.com/zorkian/go-datadog-api"
 )
 
 func TestAccDatadogMetricAlert_Basic(t *testing.T) {
@@ -98,8 +99,103 @@ func TestAccDatadogMetricAlert_Query(t *testing.T) {
 	})
 }
 
+func TestAccDatadogMetricAlert_ChangedOutsideTerraform(t *testing.T) {
+	if testAccFake == nil {
+		t.Skip("Changing monitors behind Terraform's back needs the fake API, TF_ACC is set")
+	}
+
+	var id int
+	var query string
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDatadogMetricAlertDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckDatadogMetricAlertConfigBasic,
+				Check: func(s *terraform.State) error {
+					id, _ = strconv.Atoi(s.RootModule().Resources["datadog_metric_alert.foo"].Primary.ID)
+					query = testAccFake.Monitor(id)["query"].(string)
+					return nil
+				},
+			},
+			resource.TestStep{
+				// The refresh sees the new tag, so the apply puts the old query back.
+				PreConfig: func() {
+					testAccFake.UpdateMonitor(id, map[string]interface{}{
+						"query": "avg(last_1h):avg:aws.ec2.cpu{environment:bar} by {host} > 2",
+					})
+				},
+				Config: testAccCheckDatadogMetricAlertConfigBasic,
+				Check: func(s *terraform.State) error {
+					if q := testAccFake.Monitor(id)["query"]; q != query {
+						return fmt.Errorf("Expected query %q to be restored, got %q", query, q)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func TestAccDatadogMetricAlert_WithoutTags(t *testing.T) {
+	if testAccFake == nil {
+		t.Skip("Changing monitors behind Terraform's back needs the fake API, TF_ACC is set")
+	}
+
+	var id int
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDatadogMetricAlertDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckDatadogMetricAlertConfigWithoutTags,
+				Check: func(s *terraform.State) error {
+					id, _ = strconv.Atoi(s.RootModule().Resources["datadog_metric_alert.foo"].Primary.ID)
+					return nil
+				},
+			},
+			resource.TestStep{
+				// A threshold written differently is not a change.
+				PreConfig: func() {
+					testAccFake.UpdateMonitor(id, map[string]interface{}{
+						"query": "avg(last_1h):avg:aws.ec2.cpu{*} > 5.0",
+					})
+				},
+				Config: testAccCheckDatadogMetricAlertConfigWithoutTags,
+				Check: func(s *terraform.State) error {
+					if q := testAccFake.Monitor(id)["query"]; q != "avg(last_1h):avg:aws.ec2.cpu{*} > 5.0" {
+						return fmt.Errorf("Expected the monitor to be left as it is, got query %q", q)
+					}
+					return nil
+				},
+			},
+			resource.TestStep{
+				// Restoring a changed metric leaves no diff on the tags.
+				PreConfig: func() {
+					testAccFake.UpdateMonitor(id, map[string]interface{}{
+						"query": "avg(last_1h):avg:aws.ec2.mem{*} > 5",
+					})
+				},
+				Config: testAccCheckDatadogMetricAlertConfigWithoutTags,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"datadog_metric_alert.foo", "tags.#", "0"),
+					func(s *terraform.State) error {
+						if q := testAccFake.Monitor(id)["query"]; q != "avg(last_1h):avg:aws.ec2.cpu{*} > 5" {
+							return fmt.Errorf("Expected the query to be restored, got %q", q)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
 func testAccCheckDatadogMetricAlertDestroy(s *terraform.State) error {
-	client := testAccProvider.Meta().(*datadog.Client)
+	client := testAccProvider.Meta().(*providerMeta).client
 
 	if err := destroyHelper(s, client); err != nil {
 		return err
@@ -109,7 +205,7 @@ func testAccCheckDatadogMetricAlertDestroy(s *terraform.State) error {
 
 func testAccCheckDatadogMetricAlertExists(n string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*datadog.Client)
+		client := testAccProvider.Meta().(*providerMeta).client
 		if err := existsHelper(s, client); err != nil {
 			return err
 		}
@@ -168,3 +264,20 @@ EOF
   renotify_interval = 60
 }
 `
+
+const testAccCheckDatadogMetricAlertConfigWithoutTags = `
+resource "datadog_metric_alert" "foo" {
+  name = "name for metric_alert foo"
+  message = "Metric alert foo is critical"
+
+  metric = "aws.ec2.cpu"
+  time_aggr = "avg"
+  time_window = "last_1h"
+  space_aggr = "avg"
+  operator = ">"
+
+  thresholds {
+	critical = 5
+  }
+}
+`
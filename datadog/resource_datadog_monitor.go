@@ -4,9 +4,11 @@ import (
 	"fmt"
 	"log"
 	"strconv"
+	"strings"
 
-	"encoding/json"
+	"github.com/hashicorp/terraform/config"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/ojongerius/terraform-provider-datadog/query"
 	"github.com/zorkian/go-datadog-api"
 )
 
@@ -33,23 +35,24 @@ func resourceDatadogMonitor() *schema.Resource {
 				Optional: true,
 			},
 			"query": &schema.Schema{
-				Type:     schema.TypeString,
-				Required: true,
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateMonitorQuery,
 			},
 			"type": &schema.Schema{
-				Type:     schema.TypeString,
-				Required: true,
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateMonitorType,
+			},
+			"tags": &schema.Schema{
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Set:      schema.HashString,
 			},
-			/*
-				"tags": &schema.Schema{
-					Type:     schema.TypeList,
-					Optional: true,
-					Elem:     &schema.Schema{Type: schema.TypeString},
-				},
-			*/
 
 			// Options
-			"thresholds": thresholdSchema(),
+			"thresholds": monitorThresholdSchema(),
 			"notify_no_data": &schema.Schema{
 				Type:     schema.TypeBool,
 				Optional: true,
@@ -71,10 +74,10 @@ func resourceDatadogMonitor() *schema.Resource {
 				Type:     schema.TypeInt,
 				Optional: true,
 			},
-			// TODO should actually be map[string]int
 			"silenced": &schema.Schema{
-				Type:     schema.TypeMap,
-				Optional: true,
+				Type:         schema.TypeMap,
+				Optional:     true,
+				ValidateFunc: validateSilenced,
 				Elem: &schema.Schema{
 					Type: schema.TypeString,
 					Elem: &schema.Schema{
@@ -84,50 +87,262 @@ func resourceDatadogMonitor() *schema.Resource {
 			"include_tags": &schema.Schema{
 				Type:     schema.TypeBool,
 				Optional: true,
+				Default:  true,
+			},
+			"require_full_window": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"new_host_delay": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      300,
+				ValidateFunc: validateNonNegativeInt,
+			},
+			"evaluation_delay": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validateNonNegativeInt,
+			},
+			"locked": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			"notify_by": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"threshold_windows": &schema.Schema{
+				Type:         schema.TypeMap,
+				Optional:     true,
+				ValidateFunc: validateThresholdWindows,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"trigger_window": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"recovery_window": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+					},
+				},
+			},
+
+			// Read only, set by Datadog
+			"overall_state": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"creator_handle": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"creator_email": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"created": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"modified": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"deleted": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
 			},
 		},
 	}
 }
 
-// buildMonitorStruct returns a monitor struct
-func buildMonitorStruct(d *schema.ResourceData) *datadog.Monitor {
+// monitorThresholdSchema is thresholdSchema made optional, as composite
+// monitors take no thresholds. A thresholds block still needs critical while
+// planning, and checkMonitor requires the block for other types.
+func monitorThresholdSchema() *schema.Schema {
+	s := thresholdSchema()
+	s.Required = false
+	s.Optional = true
+	return s
+}
+
+// monitorTypes are the monitor types datadog_monitor manages, with the
+// thresholds each of them takes. Event alerts only take the critical
+// threshold, which is also the one in their query. Composite monitors take
+// none, they alert on the state of the monitors in their query.
+var monitorTypes = map[string][]string{
+	"metric alert":  []string{"ok", "warning", "critical"},
+	"query alert":   []string{"ok", "warning", "critical"},
+	"service check": []string{"ok", "warning", "critical"},
+	"event alert":   []string{"critical"},
+	"composite":     []string{},
+}
+
+func validateMonitorType(v interface{}, k string) (ws []string, es []error) {
+	if _, ok := monitorTypes[v.(string)]; !ok {
+		es = append(es, fmt.Errorf(
+			"%q must be one of \"metric alert\", \"query alert\", \"service check\", \"event alert\" "+
+				"or \"composite\", got %q", k, v))
+	}
+	return
+}
+
+// validateMonitorQuery checks what can be checked of a query without knowing
+// the monitor type: the algorithm of an outliers() call.
+func validateMonitorQuery(v interface{}, k string) (ws []string, es []error) {
+	q := v.(string)
+	if !strings.Contains(q, "outliers(") || strings.Contains(q, config.UnknownVariableValue) {
+		return
+	}
+	if o, err := query.ParseOutlier(q); err == nil && !outlierAlgorithms[o.Algorithm] {
+		es = append(es, fmt.Errorf("%q: outlier algorithm must be dbscan or mad, got %q", k, o.Algorithm))
+	}
+	return
+}
+
+// validateSilenced checks each muted scope ends at a POSIX timestamp, or 0 for
+// a mute without an end.
+func validateSilenced(v interface{}, k string) (ws []string, es []error) {
+	for scope, end := range configMap(v) {
+		if _, err := strconv.Atoi(fmt.Sprint(end)); err != nil {
+			es = append(es, fmt.Errorf("%s.%s must be a POSIX timestamp or 0, got %q", k, scope, end))
+		}
+	}
+	return
+}
+
+// validateThresholdWindows checks threshold_windows only sets the trigger and
+// recovery windows. The Elem of a map is not enforced, so a misspelt window
+// would otherwise be dropped without a word.
+func validateThresholdWindows(v interface{}, k string) (ws []string, es []error) {
+	for window := range configMap(v) {
+		if window != "trigger_window" && window != "recovery_window" {
+			es = append(es, fmt.Errorf("%s: unknown window %q, must be trigger_window or recovery_window", k, window))
+		}
+	}
+	return
+}
 
+// configMap returns map attribute v, which is a list of maps when the config
+// writes it as a block.
+func configMap(v interface{}) map[string]interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		return v
+	case []map[string]interface{}:
+		m := make(map[string]interface{})
+		for _, e := range v {
+			for k, end := range e {
+				m[k] = end
+			}
+		}
+		return m
+	}
+	return nil
+}
+
+// checkMonitor checks the thresholds and query of d fit its type, and each
+// other. These checks span several fields, which helper/schema cannot
+// validate while planning, so they only run when the monitor is created or
+// updated, before anything is sent to Datadog.
+func checkMonitor(d *schema.ResourceData) error {
+	monitorType := d.Get("type").(string)
 	_, thresholds := getThresholds(d)
 
-	o := datadog.Options{
-		Thresholds: thresholds,
+	allowed := make(map[string]bool)
+	for _, name := range monitorTypes[monitorType] {
+		allowed[name] = true
+	}
+	if thresholds.Ok != "" && !allowed["ok"] {
+		return fmt.Errorf("%s monitors do not take an ok threshold", monitorType)
 	}
-	if attr, ok := d.GetOk("silenced"); ok {
-		s := make(map[string]int)
-		// TODO: this is not very defensive, test if we can fail non int input
-		for k, v := range attr.(map[string]interface{}) {
-			s[k], _ = strconv.Atoi(v.(string))
+	if thresholds.Warning != "" && !allowed["warning"] {
+		return fmt.Errorf("%s monitors do not take a warning threshold", monitorType)
+	}
+	if thresholds.Critical != "" && !allowed["critical"] {
+		return fmt.Errorf("%s monitors do not take a critical threshold", monitorType)
+	}
+	if thresholds.Critical == "" && allowed["critical"] {
+		return fmt.Errorf("%s monitors need a critical threshold", monitorType)
+	}
+
+	if monitorType == "composite" {
+		if _, err := query.ParseComposite(d.Get("query").(string)); err != nil {
+			return fmt.Errorf("composite monitors need a query combining monitor IDs with &&, || and !: %s", err)
 		}
-		o.Silenced = s
+		return nil
 	}
-	if attr, ok := d.GetOk("notify_data"); ok {
-		o.NotifyNoData = attr.(bool)
+
+	// Service check thresholds count checks, their query has no comparison.
+	if monitorType == "service check" {
+		return nil
 	}
-	if attr, ok := d.GetOk("no_data_timeframe"); ok {
-		o.NoDataTimeframe = attr.(int)
+
+	q := d.Get("query").(string)
+	c, err := query.ParseComparison(q)
+	if err != nil {
+		return fmt.Errorf("%s monitors need a query ending in a comparison with the critical threshold: %s",
+			monitorType, err)
 	}
-	if attr, ok := d.GetOk("renotify_interval"); ok {
-		o.RenotifyInterval = attr.(int)
+
+	if !sameNumber(thresholds.Critical.String(), c.Threshold) {
+		return fmt.Errorf("critical threshold %s does not match the threshold in the query, %s",
+			thresholds.Critical, c.Threshold)
 	}
-	if attr, ok := d.GetOk("notify_audit"); ok {
-		o.NotifyAudit = attr.(bool)
+
+	if _, ok := d.GetOk("threshold_windows"); ok && !strings.Contains(c.Expr, "anomalies(") {
+		return fmt.Errorf("threshold_windows only apply to anomaly monitors, the query does not use anomalies()")
 	}
-	if attr, ok := d.GetOk("timeout_h"); ok {
-		o.TimeoutH = attr.(int)
+
+	return checkThresholdOrder(c.Operator, thresholds)
+}
+
+// buildMonitorStruct returns a monitor struct
+func buildMonitorStruct(d *schema.ResourceData) *datadog.Monitor {
+
+	_, thresholds := getThresholds(d)
+
+	notifyAudit := d.Get("notify_audit").(bool)
+	timeoutH := d.Get("timeout_h").(int)
+	escalationMessage := d.Get("escalation_message").(string)
+	includeTags := d.Get("include_tags").(bool)
+	requireFullWindow := d.Get("require_full_window").(bool)
+	newHostDelay := d.Get("new_host_delay").(int)
+	evaluationDelay := d.Get("evaluation_delay").(int)
+	locked := d.Get("locked").(bool)
+	notifyBy := getStringList(d, "notify_by")
+	if notifyBy == nil {
+		notifyBy = []string{}
 	}
-	if attr, ok := d.GetOk("escalation_message"); ok {
-		o.EscalationMessage = attr.(string)
+
+	o := buildMonitorOptions(d)
+	o.Thresholds = thresholds
+	o.NotifyAudit = &notifyAudit
+	o.TimeoutH = &timeoutH
+	o.EscalationMessage = &escalationMessage
+	o.IncludeTags = &includeTags
+	o.RequireFullWindow = &requireFullWindow
+	o.NewHostDelay = &newHostDelay
+	o.EvaluationDelay = &evaluationDelay
+	o.Locked = &locked
+	o.NotifyBy = &notifyBy
+	// validateSilenced made sure these are integers.
+	for k, v := range d.Get("silenced").(map[string]interface{}) {
+		o.Silenced[k], _ = strconv.Atoi(v.(string))
 	}
-	if attr, ok := d.GetOk("escalation_message"); ok {
-		o.EscalationMessage = attr.(string)
+	if attr, ok := d.GetOk("threshold_windows.trigger_window"); ok {
+		o.ThresholdWindows = &datadog.ThresholdWindows{TriggerWindow: attr.(string)}
 	}
-	if attr, ok := d.GetOk("include_tags"); ok {
-		o.IncludeTags = attr.(bool)
+	if attr, ok := d.GetOk("threshold_windows.recovery_window"); ok {
+		if o.ThresholdWindows == nil {
+			o.ThresholdWindows = &datadog.ThresholdWindows{}
+		}
+		o.ThresholdWindows.RecoveryWindow = attr.(string)
 	}
 
 	m := datadog.Monitor{
@@ -135,147 +350,143 @@ func buildMonitorStruct(d *schema.ResourceData) *datadog.Monitor {
 		Query:   d.Get("query").(string),
 		Name:    d.Get("name").(string),
 		Message: d.Get("message").(string),
+		Tags:    getMonitorTags(d),
 		Options: o,
 	}
 
-	/*
-		if attr, ok := d.GetOk("tags"); ok {
-			m.Tags = attr.([]string)
-		}
-	*/
-
 	return &m
 }
 
+// getMonitorTags returns the tags set on the resource. It is never nil, so an
+// update that removes the last tag sends an empty list rather than nothing.
+func getMonitorTags(d *schema.ResourceData) []string {
+	tags := []string{}
+	for _, v := range d.Get("tags").(*schema.Set).List() {
+		tags = append(tags, v.(string))
+	}
+	return tags
+}
+
 // resourceDatadogMonitorCreate creates a monitor.
 func resourceDatadogMonitorCreate(d *schema.ResourceData, meta interface{}) error {
+	if err := checkMonitor(d); err != nil {
+		return err
+	}
 
 	m := buildMonitorStruct(d)
+	if err := checkCompositeMonitors(meta, m); err != nil {
+		return err
+	}
 	if err := monitorCreator(d, meta, m); err != nil {
 		return err
 	}
 
-	return nil
+	// Read back what Datadog sets, such as overall_state, so it is known
+	// right after the apply.
+	return resourceDatadogMonitorRead(d, meta)
 }
 
 // resourceDatadogMonitorRead creates a monitor.
 func resourceDatadogMonitorRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*datadog.Client)
-
-	// Workaround to handle upgrades from < 0.0.4
-
-	i, err := strconv.Atoi(d.Id())
-	if err != nil {
-		// TODO: dress/decorate error
+	m, err := readMonitor(d, meta)
+	if err != nil || m == nil {
 		return err
 	}
 
-	m, err := client.GetMonitor(i)
-
-	if err != nil {
-		// TODO: dress/decorate error
-		return err
-	}
-
-	log.Printf("[DEBUG] monitor: %v", m)
-	d.Set("name", m.Name)
-	d.Set("message", m.Message)
-	d.Set("query", m.Query)
+	readMonitorOptions(d, resourceDatadogMonitor().Schema, m)
 	d.Set("type", m.Type)
-	/*
-		d.Set("tags", m.Tags)
-	*/
-	d.Set("thresholds", m.Options.Thresholds)
-	d.Set("notify_no_data", m.Options.NotifyNoData)
-	d.Set("notify_no_data_timeframe", m.Options.NoDataTimeframe)
-	d.Set("renotify_interval", m.Options.RenotifyInterval)
-	d.Set("notify_audit", m.Options.NotifyAudit)
-	d.Set("timeout_h", m.Options.TimeoutH)
-	d.Set("escalation_message", m.Options.EscalationMessage)
-	d.Set("silenced", m.Options.Silenced)
-	d.Set("include_tags", m.Options.IncludeTags)
+	if m.Type != "composite" || !sameComposite(d.Get("query").(string), m.Query) {
+		d.Set("query", m.Query)
+	}
+	d.Set("tags", resourceTags(m.Tags, meta.(*providerMeta).defaultTags, getMonitorTags(d)))
+	readThresholds(d, m.Options.Thresholds)
+	d.Set("silenced", ownSilenced(d, m.Options.Silenced))
+
+	d.Set("overall_state", m.OverallState)
+	d.Set("creator_handle", "")
+	d.Set("creator_email", "")
+	if m.Creator != nil {
+		d.Set("creator_handle", m.Creator.Handle)
+		d.Set("creator_email", m.Creator.Email)
+	}
+	d.Set("created", m.Created)
+	d.Set("modified", m.Modified)
+	d.Set("deleted", "")
+	if m.Deleted != nil {
+		d.Set("deleted", *m.Deleted)
+	}
 
 	return nil
 }
 
-// resourceDatadogMonitorUpdate updates a monitor.
-func resourceDatadogMonitorUpdate(d *schema.ResourceData, meta interface{}) error {
-	log.Print("[DEBUG] running update.")
-	client := meta.(*datadog.Client)
-
-	m := &datadog.Monitor{}
+// checkCompositeMonitors checks the monitors a composite monitor m refers to
+// exist, so a typo in an ID is reported by name rather than as a bad request.
+func checkCompositeMonitors(meta interface{}, m *datadog.Monitor) error {
+	if m.Type != "composite" {
+		return nil
+	}
+	client := meta.(*providerMeta).client
 
-	i, err := strconv.Atoi(d.Id())
+	c, err := query.ParseComposite(m.Query)
 	if err != nil {
 		return err
 	}
-
-	m.Id = i
-
-	if attr, ok := d.GetOk("name"); ok {
-		m.Name = attr.(string)
-	}
-	if attr, ok := d.GetOk("message"); ok {
-		m.Message = attr.(string)
-	}
-	if attr, ok := d.GetOk("query"); ok {
-		m.Query = attr.(string)
-	}
-	/*
-		if attr, ok := d.GetOk("tags"); ok {
-			m.Tags = attr.([]string)
+	for _, id := range c.IDs() {
+		if _, err := client.GetMonitor(id); datadog.IsNotFound(err) {
+			return fmt.Errorf("composite monitor %q refers to monitor %d, which does not exist", m.Name, id)
+		} else if err != nil {
+			return fmt.Errorf("error reading monitor %d: %s", id, err)
 		}
-	*/
+	}
+	return nil
+}
 
-	o := datadog.Options{}
+// sameComposite reports whether composite queries a and b only differ in
+// spacing, so a query as written is kept when Datadog formats it differently.
+func sameComposite(a, b string) bool {
+	qa, err := query.ParseComposite(a)
+	if err != nil {
+		return false
+	}
+	qb, err := query.ParseComposite(b)
+	return err == nil && query.Equal(qa, qb)
+}
 
-	if attr, ok := d.GetOk("thresholds"); ok {
-		thresholds := attr.(map[string]interface{})
-		if thresholds["ok"] != nil {
-			o.Thresholds.Ok = json.Number(thresholds["ok"].(string))
-		}
-		if thresholds["warning"] != nil {
-			o.Thresholds.Warning = json.Number(thresholds["warning"].(string))
-		}
-		if thresholds["critical"] != nil {
-			o.Thresholds.Critical = json.Number(thresholds["critical"].(string))
+// ownSilenced returns the mutes in silenced that d manages. Scopes muted by
+// datadog_monitor_mute, or by hand, are left out so they do not show as a diff.
+func ownSilenced(d *schema.ResourceData, silenced map[string]int) map[string]int {
+	own := make(map[string]int)
+	for scope := range d.Get("silenced").(map[string]interface{}) {
+		if end, ok := silenced[scope]; ok {
+			own[scope] = end
 		}
 	}
+	return own
+}
 
-	if attr, ok := d.GetOk("notify_no_data"); ok {
-		o.NotifyNoData = attr.(bool)
-	}
-	if attr, ok := d.GetOk("notify_no_data_timeframe"); ok {
-		o.NoDataTimeframe = attr.(int)
-	}
-	if attr, ok := d.GetOk("renotify_interval"); ok {
-		o.RenotifyInterval = attr.(int)
-	}
-	if attr, ok := d.GetOk("notify_audit"); ok {
-		o.NotifyAudit = attr.(bool)
-	}
-	if attr, ok := d.GetOk("timeout_h"); ok {
-		o.TimeoutH = attr.(int)
-	}
-	if attr, ok := d.GetOk("escalation_message"); ok {
-		o.EscalationMessage = attr.(string)
+// resourceDatadogMonitorUpdate updates a monitor.
+func resourceDatadogMonitorUpdate(d *schema.ResourceData, meta interface{}) error {
+	log.Print("[DEBUG] running update.")
+
+	if err := checkMonitor(d); err != nil {
+		return err
 	}
-	if attr, ok := d.GetOk("silenced"); ok {
-		// TODO: this is not very defensive, test if we can fail non int input
-		s := make(map[string]int)
-		for k, v := range attr.(map[string]interface{}) {
-			s[k], _ = strconv.Atoi(v.(string))
+
+	// Scopes removed from silenced are unmuted, all other mutes are kept.
+	owned := make(map[string]bool)
+	o, n := d.GetChange("silenced")
+	for _, v := range []interface{}{o, n} {
+		for scope := range v.(map[string]interface{}) {
+			owned[scope] = true
 		}
-		o.Silenced = s
 	}
-	if attr, ok := d.GetOk("include_tags"); ok {
-		o.IncludeTags = attr.(bool)
-	}
-
-	m.Options = o
 
-	if err := client.UpdateMonitor(m); err != nil {
-		return fmt.Errorf("error updating monitor: %s", err.Error())
+	m := buildMonitorStruct(d)
+	if err := checkCompositeMonitors(meta, m); err != nil {
+		return err
+	}
+	if err := monitorUpdater(d, meta, m, owned); err != nil {
+		return err
 	}
 
 	return resourceDatadogMonitorRead(d, meta)
@@ -0,0 +1,157 @@
+package datadog
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/zorkian/go-datadog-api"
+)
+
+// monitorSchemaVersion is the schema version of the metric alert, service
+// check and outlier alert resources. Version 0 states may hold an ID from
+// before 0.0.4, when a resource created a monitor per threshold and joined
+// their IDs with "__".
+const monitorSchemaVersion = 1
+
+// migrateMonitorState migrates the state of the metric alert, service check
+// and outlier alert resources to monitorSchemaVersion.
+func migrateMonitorState(v int, is *terraform.InstanceState, meta interface{}) (*terraform.InstanceState, error) {
+	switch v {
+	case 0:
+		log.Println("[INFO] Found Datadog monitor state v0; migrating to v1")
+		return migrateMonitorStateV0toV1(is, meta)
+	default:
+		return is, fmt.Errorf("unexpected schema version: %d", v)
+	}
+}
+
+// migrateMonitorStateV0toV1 rewrites a "__" joined ID to the ID of the one
+// monitor the resource now manages, the first of them that still exists. The
+// other monitors are left over. They are kept in legacy_monitor_ids, so
+// terraform show lists them and destroying the resource deletes them.
+//
+// The state is changed in place, as Refresh drops the returned state.
+func migrateMonitorStateV0toV1(is *terraform.InstanceState, meta interface{}) (*terraform.InstanceState, error) {
+	if is.Empty() || !strings.Contains(is.ID, "__") {
+		log.Println("[DEBUG] Empty InstanceState or current ID; nothing to migrate.")
+		return is, nil
+	}
+
+	ids, err := parseLegacyMonitorID(is.ID)
+	if err != nil {
+		return is, err
+	}
+
+	kept, leftover, err := findLegacyMonitors(meta.(*providerMeta).client, ids)
+	if err != nil {
+		return is, err
+	}
+	if kept == 0 {
+		return is, fmt.Errorf("none of the monitors in pre 0.0.4 ID %q exist", is.ID)
+	}
+
+	if len(leftover) > 0 {
+		log.Printf("[WARN] Monitor %d replaces pre 0.0.4 ID %q. Monitors %s are left over, they are kept in "+
+			"legacy_monitor_ids and deleted with the resource", kept, is.ID, strings.Join(intsToStrings(leftover), ", "))
+		is.Attributes["legacy_monitor_ids.#"] = strconv.Itoa(len(leftover))
+		for n, i := range leftover {
+			is.Attributes[fmt.Sprintf("legacy_monitor_ids.%d", n)] = strconv.Itoa(i)
+		}
+	}
+
+	log.Printf("[DEBUG] Monitor ID before migration: %s", is.ID)
+	is.ID = strconv.Itoa(kept)
+	log.Printf("[DEBUG] Monitor ID after migration: %s", is.ID)
+
+	return is, nil
+}
+
+// legacyMonitorIDsSchema holds the monitors left over when a pre 0.0.4 ID is
+// migrated.
+func legacyMonitorIDsSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Computed: true,
+		Elem:     &schema.Schema{Type: schema.TypeInt},
+	}
+}
+
+// readLegacyMonitorIDs keeps the left over monitors of d as they are, as only
+// the state knows them. Setting them makes a new resource's empty list known.
+func readLegacyMonitorIDs(d *schema.ResourceData) {
+	d.Set("legacy_monitor_ids", d.Get("legacy_monitor_ids"))
+}
+
+// resourceDatadogLegacyMonitorDelete deletes the monitor of a metric alert,
+// service check or outlier alert resource, then the monitors left over from a
+// pre 0.0.4 ID. Those that cannot be deleted stay in legacy_monitor_ids and
+// are named in the error, so the next destroy, or the user, can retry.
+func resourceDatadogLegacyMonitorDelete(d *schema.ResourceData, meta interface{}) error {
+	if err := resourceDatadogGenericDelete(d, meta); err != nil {
+		return err
+	}
+
+	client := meta.(*providerMeta).client
+	var failed []int
+	var errs []string
+	for _, v := range d.Get("legacy_monitor_ids").([]interface{}) {
+		i := v.(int)
+		if err := client.DeleteMonitor(i); err != nil && !datadog.IsNotFound(err) {
+			failed = append(failed, i)
+			errs = append(errs, fmt.Sprintf("monitor %d: %s", i, err))
+		}
+	}
+
+	if len(failed) > 0 {
+		d.Set("legacy_monitor_ids", failed)
+		return fmt.Errorf("monitor %s was deleted, but monitors %s left over from its pre 0.0.4 ID were not, "+
+			"destroy again or delete them by hand: %s",
+			d.Id(), strings.Join(intsToStrings(failed), ", "), strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+// parseLegacyMonitorID splits a pre 0.0.4 ID into monitor IDs.
+func parseLegacyMonitorID(id string) ([]int, error) {
+	var ids []int
+	for _, part := range strings.Split(id, "__") {
+		i, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, fmt.Errorf("pre 0.0.4 ID %q is not made of monitor IDs joined by __", id)
+		}
+		ids = append(ids, i)
+	}
+	return ids, nil
+}
+
+// findLegacyMonitors returns the first of ids that still exists as kept, and
+// the other existing ones as leftover. kept is 0 when none of them exist.
+func findLegacyMonitors(client *datadog.Client, ids []int) (kept int, leftover []int, err error) {
+	for _, i := range ids {
+		if _, err := client.GetMonitor(i); datadog.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return 0, nil, fmt.Errorf("error reading monitor %d: %s", i, err)
+		}
+
+		if kept == 0 {
+			kept = i
+		} else {
+			leftover = append(leftover, i)
+		}
+	}
+	return kept, leftover, nil
+}
+
+func intsToStrings(ints []int) []string {
+	s := make([]string, len(ints))
+	for i, v := range ints {
+		s[i] = strconv.Itoa(v)
+	}
+	return s
+}
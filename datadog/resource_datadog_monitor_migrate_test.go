@@ -0,0 +1,230 @@
+package datadog
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/zorkian/go-datadog-api"
+)
+
+// legacyMonitorServer serves the monitors in exists, and 404 for any other.
+func legacyMonitorServer(exists ...int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, id := range exists {
+			if r.URL.Path == fmt.Sprintf("/api/v1/monitor/%d", id) {
+				fmt.Fprintf(w, `{"id": %d, "type": "metric alert"}`, id)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"errors": ["Monitor not found"]}`))
+	}))
+}
+
+func TestMigrateMonitorState(t *testing.T) {
+	cases := []struct {
+		Name       string
+		StateVer   int
+		ID         string
+		Exists     []int
+		Attributes map[string]string
+		ExpectedID string
+		Leftover   []int
+		Err        string
+	}{
+		{
+			Name:       "current ID",
+			StateVer:   0,
+			ID:         "123",
+			Attributes: map[string]string{"name": "foo"},
+			ExpectedID: "123",
+		},
+		{
+			Name:       "warning and critical monitors",
+			StateVer:   0,
+			ID:         "123__456",
+			Exists:     []int{123, 456},
+			Attributes: map[string]string{"name": "foo", "thresholds.critical": "3"},
+			ExpectedID: "123",
+			Leftover:   []int{456},
+		},
+		{
+			Name:       "first monitor deleted",
+			StateVer:   0,
+			ID:         "123__456",
+			Exists:     []int{456},
+			Attributes: map[string]string{"name": "foo"},
+			ExpectedID: "456",
+		},
+		{
+			Name:       "no monitor left",
+			StateVer:   0,
+			ID:         "123__456",
+			Attributes: map[string]string{"name": "foo"},
+			Err:        `none of the monitors in pre 0.0.4 ID "123__456" exist`,
+		},
+		{
+			Name:       "not monitor IDs",
+			StateVer:   0,
+			ID:         "foo__456",
+			Attributes: map[string]string{"name": "foo"},
+			Err:        `pre 0.0.4 ID "foo__456" is not made of monitor IDs joined by __`,
+		},
+		{
+			Name:       "unknown version",
+			StateVer:   2,
+			ID:         "123",
+			Attributes: map[string]string{"name": "foo"},
+			Err:        "unexpected schema version: 2",
+		},
+	}
+
+	for _, tc := range cases {
+		ts := legacyMonitorServer(tc.Exists...)
+		c := Config{APIKey: "foo", APPKey: "bar", APIURL: ts.URL}
+		client, err := c.Client()
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		attributes := make(map[string]string)
+		for k, v := range tc.Attributes {
+			attributes[k] = v
+		}
+		is := &terraform.InstanceState{ID: tc.ID, Attributes: attributes}
+		is, err = migrateMonitorState(tc.StateVer, is, &providerMeta{client: client})
+		ts.Close()
+
+		if tc.Err != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.Err) {
+				t.Fatalf("%s: expected error %q, got %v", tc.Name, tc.Err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: err: %s", tc.Name, err)
+		}
+		if is.ID != tc.ExpectedID {
+			t.Fatalf("%s: expected ID %q, got %q", tc.Name, tc.ExpectedID, is.ID)
+		}
+		expected := make(map[string]string)
+		for k, v := range tc.Attributes {
+			expected[k] = v
+		}
+		if len(tc.Leftover) > 0 {
+			expected["legacy_monitor_ids.#"] = fmt.Sprint(len(tc.Leftover))
+			for n, i := range tc.Leftover {
+				expected[fmt.Sprintf("legacy_monitor_ids.%d", n)] = fmt.Sprint(i)
+			}
+		}
+		if !reflect.DeepEqual(is.Attributes, expected) {
+			t.Fatalf("%s: expected attributes %v, got %v", tc.Name, expected, is.Attributes)
+		}
+	}
+}
+
+func TestFindLegacyMonitors(t *testing.T) {
+	ts := legacyMonitorServer(2, 3, 4)
+	defer ts.Close()
+	c := Config{APIKey: "foo", APPKey: "bar", APIURL: ts.URL}
+	client, err := c.Client()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	kept, leftover, err := findLegacyMonitors(client, []int{1, 2, 3, 4})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if kept != 2 || !reflect.DeepEqual(leftover, []int{3, 4}) {
+		t.Fatalf("Expected to keep 2 and leave 3 and 4 over, got %d and %v", kept, leftover)
+	}
+}
+
+func TestMetricAlert_RefreshLegacyID(t *testing.T) {
+	if testAccFake == nil {
+		t.Skip("Refreshing a pre 0.0.4 ID needs the fake API, TF_ACC is set")
+	}
+
+	meta := testAccFake.Meta(t)
+	var ids []int
+	for _, name := range []string{"foo warning", "foo critical"} {
+		m, err := meta.client.CreateMonitor(&datadog.Monitor{Name: name, Type: "metric alert",
+			Query: "avg(last_1h):avg:aws.ec2.cpu{*} > 2"})
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		defer testAccFake.DeleteMonitor(m.Id)
+		ids = append(ids, m.Id)
+	}
+
+	is := &terraform.InstanceState{
+		ID:         fmt.Sprintf("%d__%d", ids[0], ids[1]),
+		Attributes: map[string]string{"name": "foo warning", "metric": "aws.ec2.cpu"},
+	}
+	is, err := resourceDatadogMetricAlert().Refresh(is, meta)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if expected := fmt.Sprint(ids[0]); is == nil || is.ID != expected {
+		t.Fatalf("Expected the refreshed state to have ID %s, got %#v", expected, is)
+	}
+	if v := is.Meta["schema_version"]; v != "1" {
+		t.Fatalf("Expected schema version 1 to be recorded, got %q", v)
+	}
+	if left := is.Attributes["legacy_monitor_ids.0"]; left != fmt.Sprint(ids[1]) {
+		t.Fatalf("Expected monitor %d to be kept as left over, got %v", ids[1], is.Attributes)
+	}
+}
+
+func TestLegacyMonitorDelete(t *testing.T) {
+	if testAccFake == nil {
+		t.Skip("Deleting left over monitors needs the fake API, TF_ACC is set")
+	}
+
+	meta := testAccFake.Meta(t)
+	var ids []int
+	for _, name := range []string{"foo ok", "foo warning", "foo critical"} {
+		m, err := meta.client.CreateMonitor(&datadog.Monitor{Name: name, Type: "metric alert",
+			Query: "avg(last_1h):avg:aws.ec2.cpu{*} > 2"})
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		defer testAccFake.DeleteMonitor(m.Id)
+		ids = append(ids, m.Id)
+	}
+
+	d := resourceDatadogMetricAlert().TestResourceData()
+	d.SetId(fmt.Sprint(ids[0]))
+	d.Set("legacy_monitor_ids", ids[1:])
+
+	// A left over monitor that cannot be deleted is named, and kept for the
+	// next destroy.
+	testAccFake.Inject(&fakeFault{Method: "DELETE", Path: fmt.Sprintf("/api/v1/monitor/%d", ids[2]),
+		Status: http.StatusForbidden, Count: 1})
+	err := resourceDatadogLegacyMonitorDelete(d, meta)
+	expected := fmt.Sprintf("monitors %d left over", ids[2])
+	if err == nil || !strings.Contains(err.Error(), expected) {
+		t.Fatalf("Expected an error naming monitor %d, got %v", ids[2], err)
+	}
+	if left := d.Get("legacy_monitor_ids").([]interface{}); !reflect.DeepEqual(left, []interface{}{ids[2]}) {
+		t.Fatalf("Expected only monitor %d to be left over, got %v", ids[2], left)
+	}
+	for _, i := range ids[:2] {
+		if testAccFake.Monitor(i) != nil {
+			t.Fatalf("Expected monitor %d to be deleted", i)
+		}
+	}
+
+	if err := resourceDatadogLegacyMonitorDelete(d, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if testAccFake.Monitor(ids[2]) != nil {
+		t.Fatalf("Expected monitor %d to be deleted", ids[2])
+	}
+}
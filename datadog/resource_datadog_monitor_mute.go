@@ -0,0 +1,149 @@
+package datadog
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/zorkian/go-datadog-api"
+)
+
+// resourceDatadogMonitorMute mutes a monitor for a scope. Its ID is the
+// monitor ID and scope joined by ":".
+func resourceDatadogMonitorMute() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceDatadogMonitorMuteCreate,
+		Read:   resourceDatadogMonitorMuteRead,
+		Update: resourceDatadogMonitorMuteUpdate,
+		Delete: resourceDatadogMonitorMuteDelete,
+
+		Schema: map[string]*schema.Schema{
+			"monitor_id": &schema.Schema{
+				Type:     schema.TypeInt,
+				Required: true,
+				ForceNew: true,
+			},
+			"scope": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				Default:  "*",
+			},
+			"end": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRFC3339,
+			},
+		},
+	}
+}
+
+// parseMonitorMuteID splits a mute ID into its monitor ID and scope.
+func parseMonitorMuteID(id string) (int, string, error) {
+	parts := strings.SplitN(id, ":", 2)
+	if len(parts) != 2 {
+		return 0, "", fmt.Errorf("monitor mute ID %q is not of the form <monitor id>:<scope>", id)
+	}
+	i, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, "", fmt.Errorf("monitor mute ID %q does not start with a monitor ID", id)
+	}
+	return i, parts[1], nil
+}
+
+// muteMonitor mutes the monitor of d until the end of d.
+func muteMonitor(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*providerMeta).client
+
+	end, err := getTime(d, "end")
+	if err != nil {
+		return err
+	}
+	opts := &datadog.MuteMonitorOptions{Scope: d.Get("scope").(string), End: end}
+
+	id := d.Get("monitor_id").(int)
+	if _, err := client.MuteMonitorScope(id, opts); err != nil {
+		return fmt.Errorf("error muting monitor %d for scope %q: %s", id, opts.Scope, err)
+	}
+
+	d.SetId(fmt.Sprintf("%d:%s", id, opts.Scope))
+	return nil
+}
+
+func resourceDatadogMonitorMuteCreate(d *schema.ResourceData, meta interface{}) error {
+	if err := muteMonitor(d, meta); err != nil {
+		return err
+	}
+
+	return resourceDatadogMonitorMuteRead(d, meta)
+}
+
+// resourceDatadogMonitorMuteRead removes the mute from state when the monitor
+// is gone, or the scope was unmuted by hand. Datadog also unmutes the scope
+// once the mute ends, that mute is kept as it is, as muting again with an end
+// in the past would fail.
+func resourceDatadogMonitorMuteRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*providerMeta).client
+
+	id, scope, err := parseMonitorMuteID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	m, err := client.GetMonitor(id)
+	if datadog.IsNotFound(err) {
+		log.Printf("[WARN] monitor %d not found, removing its mute from state", id)
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error reading monitor %d: %s", id, err)
+	}
+
+	end, ok := m.Options.Silenced[scope]
+	if !ok {
+		oldEnd, err := getTime(d, "end")
+		if err == nil && oldEnd != 0 && oldEnd <= time.Now().Unix() {
+			log.Printf("[INFO] the mute of monitor %d for scope %q ended, keeping it in state", id, scope)
+			return nil
+		}
+		log.Printf("[WARN] monitor %d is no longer muted for scope %q, removing the mute from state", id, scope)
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("monitor_id", id)
+	d.Set("scope", scope)
+	setTime(d, "end", int64(end))
+
+	return nil
+}
+
+// resourceDatadogMonitorMuteUpdate mutes the scope again with the new end.
+func resourceDatadogMonitorMuteUpdate(d *schema.ResourceData, meta interface{}) error {
+	if err := muteMonitor(d, meta); err != nil {
+		return err
+	}
+
+	return resourceDatadogMonitorMuteRead(d, meta)
+}
+
+func resourceDatadogMonitorMuteDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*providerMeta).client
+
+	id, scope, err := parseMonitorMuteID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	// A monitor that is already gone needs no unmuting.
+	_, err = client.UnmuteMonitorScope(id, &datadog.UnmuteMonitorOptions{Scope: scope})
+	if err != nil && !datadog.IsNotFound(err) {
+		return fmt.Errorf("error unmuting monitor %d for scope %q: %s", id, scope, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,294 @@
+package datadog
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccDatadogMonitorMute_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDatadogMonitorDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckDatadogMonitorMuteConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDatadogMonitorExists("datadog_monitor.foo"),
+					resource.TestCheckResourceAttr(
+						"datadog_monitor_mute.foo", "scope", "role:db"),
+					resource.TestCheckResourceAttr(
+						"datadog_monitor_mute.foo", "end", "2030-01-02T15:04:05Z"),
+					resource.TestCheckResourceAttr(
+						"datadog_monitor.foo", "silenced.#", "1"),
+					testAccCheckDatadogMonitorMuted("datadog_monitor.foo", "role:db", "1893596645"),
+					testAccCheckDatadogMonitorMuted("datadog_monitor.foo", "host:foo", "0"),
+				),
+			},
+			resource.TestStep{
+				// Updating the monitor keeps the mute it does not own.
+				Config: testAccCheckDatadogMonitorMuteConfigMonitorUpdated,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"datadog_monitor.foo", "message", "some updated message"),
+					resource.TestCheckResourceAttr(
+						"datadog_monitor.foo", "silenced.#", "1"),
+					testAccCheckDatadogMonitorMuted("datadog_monitor.foo", "role:db", "1893596645"),
+				),
+			},
+			resource.TestStep{
+				Config: testAccCheckDatadogMonitorMuteConfigEndUpdated,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"datadog_monitor_mute.foo", "end", "2031-01-02T15:04:05Z"),
+					testAccCheckDatadogMonitorMuted("datadog_monitor.foo", "role:db", "1925132645"),
+				),
+			},
+			resource.TestStep{
+				// Destroying the mute unmutes the scope, and only that scope.
+				Config: testAccCheckDatadogMonitorMuteConfigUnmuted,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDatadogMonitorNotMuted("datadog_monitor.foo", "role:db"),
+					testAccCheckDatadogMonitorMuted("datadog_monitor.foo", "host:foo", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDatadogMonitorMute_UnmutedOutsideTerraform(t *testing.T) {
+	if testAccFake == nil {
+		t.Skip("Unmuting outside Terraform needs the fake API, TF_ACC is set")
+	}
+
+	var id int
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDatadogMonitorDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckDatadogMonitorMuteConfig,
+				Check: func(s *terraform.State) error {
+					id, _ = strconv.Atoi(s.RootModule().Resources["datadog_monitor.foo"].Primary.ID)
+					return nil
+				},
+			},
+			resource.TestStep{
+				// The scope was unmuted by hand before the end, so it is muted again.
+				PreConfig: func() {
+					options := testAccFake.Monitor(id)["options"].(map[string]interface{})
+					options["silenced"] = map[string]interface{}{"host:foo": 0}
+					testAccFake.UpdateMonitor(id, map[string]interface{}{"options": options})
+				},
+				Config: testAccCheckDatadogMonitorMuteConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDatadogMonitorMuted("datadog_monitor.foo", "role:db", "1893596645"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDatadogMonitorMute_Ended(t *testing.T) {
+	if testAccFake == nil {
+		t.Skip("Ending a mute in the past needs the fake API, TF_ACC is set")
+	}
+
+	var id int
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDatadogMonitorDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckDatadogMonitorMuteConfigEnded,
+				Check: func(s *terraform.State) error {
+					id, _ = strconv.Atoi(s.RootModule().Resources["datadog_monitor.foo"].Primary.ID)
+					return nil
+				},
+			},
+			resource.TestStep{
+				// Datadog unmutes the scope once the mute ends. The mute is kept
+				// as it is rather than muted again with an end in the past.
+				PreConfig: func() {
+					options := testAccFake.Monitor(id)["options"].(map[string]interface{})
+					options["silenced"] = map[string]interface{}{}
+					testAccFake.UpdateMonitor(id, map[string]interface{}{"options": options})
+				},
+				Config: testAccCheckDatadogMonitorMuteConfigEnded,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"datadog_monitor_mute.foo", "end", "2001-01-02T15:04:05Z"),
+					testAccCheckDatadogMonitorNotMuted("datadog_monitor.foo", "role:db"),
+				),
+			},
+		},
+	})
+}
+
+func TestParseMonitorMuteID(t *testing.T) {
+	id, scope, err := parseMonitorMuteID("123:host:foo")
+	if err != nil || id != 123 || scope != "host:foo" {
+		t.Fatalf("Expected 123 and host:foo, got %d, %q and %v", id, scope, err)
+	}
+
+	for _, bad := range []string{"123", "foo:*", ""} {
+		if _, _, err := parseMonitorMuteID(bad); err == nil || !strings.Contains(err.Error(), strconv.Quote(bad)) {
+			t.Fatalf("Expected an error naming %q, got %v", bad, err)
+		}
+	}
+}
+
+// testAccCheckDatadogMonitorMuted checks the monitor behind resource n is
+// muted for scope until end.
+func testAccCheckDatadogMonitorMuted(n, scope, end string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*providerMeta).client
+		i, _ := strconv.Atoi(s.RootModule().Resources[n].Primary.ID)
+		m, err := client.GetMonitor(i)
+		if err != nil {
+			return fmt.Errorf("Received an error retrieving monitor %s", err)
+		}
+
+		got, ok := m.Options.Silenced[scope]
+		if !ok {
+			return fmt.Errorf("Expected monitor %d to be muted for %s, got %v", i, scope, m.Options.Silenced)
+		}
+		if strconv.Itoa(got) != end {
+			return fmt.Errorf("Expected monitor %d to be muted for %s until %s, got %d", i, scope, end, got)
+		}
+		return nil
+	}
+}
+
+func testAccCheckDatadogMonitorNotMuted(n, scope string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*providerMeta).client
+		i, _ := strconv.Atoi(s.RootModule().Resources[n].Primary.ID)
+		m, err := client.GetMonitor(i)
+		if err != nil {
+			return fmt.Errorf("Received an error retrieving monitor %s", err)
+		}
+
+		if _, ok := m.Options.Silenced[scope]; ok {
+			return fmt.Errorf("Expected monitor %d not to be muted for %s, got %v", i, scope, m.Options.Silenced)
+		}
+		return nil
+	}
+}
+
+const testAccCheckDatadogMonitorMuteConfig = `
+resource "datadog_monitor" "foo" {
+  name = "name for monitor foo"
+  type = "metric alert"
+  message = "some message Notify: @hipchat-channel"
+
+  query = "avg(last_1h):avg:aws.ec2.cpu{environment:foo,host:foo} by {host} > 2"
+
+  thresholds {
+	critical = 2
+  }
+
+  silenced {
+	"host:foo" = 0
+  }
+}
+
+resource "datadog_monitor_mute" "foo" {
+  monitor_id = "${datadog_monitor.foo.id}"
+  scope = "role:db"
+  end = "2030-01-02T15:04:05Z"
+}
+`
+
+const testAccCheckDatadogMonitorMuteConfigMonitorUpdated = `
+resource "datadog_monitor" "foo" {
+  name = "name for monitor foo"
+  type = "metric alert"
+  message = "some updated message"
+
+  query = "avg(last_1h):avg:aws.ec2.cpu{environment:foo,host:foo} by {host} > 2"
+
+  thresholds {
+	critical = 2
+  }
+
+  silenced {
+	"host:foo" = 0
+  }
+}
+
+resource "datadog_monitor_mute" "foo" {
+  monitor_id = "${datadog_monitor.foo.id}"
+  scope = "role:db"
+  end = "2030-01-02T15:04:05Z"
+}
+`
+
+const testAccCheckDatadogMonitorMuteConfigEndUpdated = `
+resource "datadog_monitor" "foo" {
+  name = "name for monitor foo"
+  type = "metric alert"
+  message = "some updated message"
+
+  query = "avg(last_1h):avg:aws.ec2.cpu{environment:foo,host:foo} by {host} > 2"
+
+  thresholds {
+	critical = 2
+  }
+
+  silenced {
+	"host:foo" = 0
+  }
+}
+
+resource "datadog_monitor_mute" "foo" {
+  monitor_id = "${datadog_monitor.foo.id}"
+  scope = "role:db"
+  end = "2031-01-02T15:04:05Z"
+}
+`
+
+const testAccCheckDatadogMonitorMuteConfigUnmuted = `
+resource "datadog_monitor" "foo" {
+  name = "name for monitor foo"
+  type = "metric alert"
+  message = "some updated message"
+
+  query = "avg(last_1h):avg:aws.ec2.cpu{environment:foo,host:foo} by {host} > 2"
+
+  thresholds {
+	critical = 2
+  }
+
+  silenced {
+	"host:foo" = 0
+  }
+}
+`
+
+const testAccCheckDatadogMonitorMuteConfigEnded = `
+resource "datadog_monitor" "foo" {
+  name = "name for monitor foo"
+  type = "metric alert"
+  message = "some message Notify: @hipchat-channel"
+
+  query = "avg(last_1h):avg:aws.ec2.cpu{*} > 2"
+
+  thresholds {
+	critical = 2
+  }
+}
+
+resource "datadog_monitor_mute" "foo" {
+  monitor_id = "${datadog_monitor.foo.id}"
+  scope = "role:db"
+  end = "2001-01-02T15:04:05Z"
+}
+`
@@ -1,8 +1,16 @@
 package datadog
 
 import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/terraform/config"
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/terraform"
 	"github.com/zorkian/go-datadog-api"
@@ -118,8 +126,557 @@ func TestAccDatadogMonitor_Updated(t *testing.T) {
 	})
 }
 
+func TestAccDatadogMonitor_Faults(t *testing.T) {
+	if testAccFake == nil {
+		t.Skip("Fault injection needs the fake API, TF_ACC is set")
+	}
+
+	testAccFake.Inject(&fakeFault{Method: "POST", Path: "/api/v1/monitor", Status: 429, Header: map[string]string{"Retry-After": "0"}, Count: 1})
+	testAccFake.Inject(&fakeFault{Method: "GET", Path: "/api/v1/monitor/", Status: 503, Count: 2})
+	testAccFake.Inject(&fakeFault{Method: "PUT", Path: "/api/v1/monitor/", Delay: 100 * time.Millisecond, Count: 1})
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDatadogMonitorDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckDatadogMonitorConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDatadogMonitorExists("datadog_monitor.foo"),
+				),
+			},
+			resource.TestStep{
+				Config: testAccCheckDatadogMonitorConfigUpdated,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDatadogMonitorExists("datadog_monitor.foo"),
+					resource.TestCheckResourceAttr(
+						"datadog_monitor.foo", "name", "name for monitor bar"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDatadogMonitor_DeletedOutsideTerraform(t *testing.T) {
+	if testAccFake == nil {
+		t.Skip("Deleting behind Terraform's back needs the fake API, TF_ACC is set")
+	}
+
+	var id string
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDatadogMonitorDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckDatadogMonitorConfig,
+				Check: func(s *terraform.State) error {
+					id = s.RootModule().Resources["datadog_monitor.foo"].Primary.ID
+					return nil
+				},
+			},
+			resource.TestStep{
+				PreConfig: func() {
+					i, _ := strconv.Atoi(id)
+					testAccFake.DeleteMonitor(i)
+				},
+				Config: testAccCheckDatadogMonitorConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDatadogMonitorExists("datadog_monitor.foo"),
+					func(s *terraform.State) error {
+						if s.RootModule().Resources["datadog_monitor.foo"].Primary.ID == id {
+							return fmt.Errorf("Expected monitor %s to be recreated with a new ID", id)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccDatadogMonitor_Tags(t *testing.T) {
+	var id string
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDatadogMonitorDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckDatadogMonitorConfigTags,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDatadogMonitorExists("datadog_monitor.foo"),
+					resource.TestCheckResourceAttr(
+						"datadog_monitor.foo", "tags.#", "2"),
+					testAccCheckDatadogMonitorTags("datadog_monitor.foo", "foo:bar", "baz"),
+					func(s *terraform.State) error {
+						id = s.RootModule().Resources["datadog_monitor.foo"].Primary.ID
+						return nil
+					},
+				),
+			},
+			resource.TestStep{
+				// Tags come back in a different order, which is no change.
+				PreConfig: func() {
+					if testAccFake != nil {
+						i, _ := strconv.Atoi(id)
+						testAccFake.UpdateMonitor(i, map[string]interface{}{"tags": []string{"baz", "foo:bar"}})
+					}
+				},
+				Config: testAccCheckDatadogMonitorConfigTags,
+			},
+			resource.TestStep{
+				Config: testAccCheckDatadogMonitorConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"datadog_monitor.foo", "tags.#", "0"),
+					testAccCheckDatadogMonitorTags("datadog_monitor.foo"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDatadogMonitor_ThresholdFormatting(t *testing.T) {
+	if testAccFake == nil {
+		t.Skip("Reformatting thresholds behind Terraform's back needs the fake API, TF_ACC is set")
+	}
+
+	var id int
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDatadogMonitorDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckDatadogMonitorConfigThresholdFormatting,
+				Check: resource.ComposeTestCheckFunc(
+					func(s *terraform.State) error {
+						id, _ = strconv.Atoi(s.RootModule().Resources["datadog_monitor.foo"].Primary.ID)
+						thresholds := testAccFake.Monitor(id)["options"].(map[string]interface{})["thresholds"]
+						if critical := thresholds.(map[string]interface{})["critical"]; fmt.Sprint(critical) != "2" {
+							return fmt.Errorf("Expected the critical threshold to be sent as 2, got %v", critical)
+						}
+						return nil
+					},
+					resource.TestCheckResourceAttr(
+						"datadog_monitor.foo", "thresholds.critical", "2.0"),
+				),
+			},
+			resource.TestStep{
+				// Datadog returning 1.0 for 1e0 is no change either.
+				PreConfig: func() {
+					options := testAccFake.Monitor(id)["options"].(map[string]interface{})
+					options["thresholds"] = map[string]interface{}{
+						"ok": json.Number("0"), "warning": json.Number("1.0"), "critical": json.Number("2")}
+					testAccFake.UpdateMonitor(id, map[string]interface{}{"options": options})
+				},
+				Config: testAccCheckDatadogMonitorConfigThresholdFormatting,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"datadog_monitor.foo", "thresholds.warning", "1e0"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDatadogMonitor_Options(t *testing.T) {
+	var id int
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDatadogMonitorDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckDatadogMonitorConfigOptions,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDatadogMonitorExists("datadog_monitor.foo"),
+					resource.TestCheckResourceAttr(
+						"datadog_monitor.foo", "require_full_window", "false"),
+					resource.TestCheckResourceAttr(
+						"datadog_monitor.foo", "new_host_delay", "600"),
+					resource.TestCheckResourceAttr(
+						"datadog_monitor.foo", "evaluation_delay", "900"),
+					resource.TestCheckResourceAttr(
+						"datadog_monitor.foo", "locked", "true"),
+					resource.TestCheckResourceAttr(
+						"datadog_monitor.foo", "include_tags", "false"),
+					resource.TestCheckResourceAttr(
+						"datadog_monitor.foo", "notify_by.#", "1"),
+					resource.TestCheckResourceAttr(
+						"datadog_monitor.foo", "notify_by.0", "host"),
+					resource.TestCheckResourceAttr(
+						"datadog_monitor.foo", "threshold_windows.trigger_window", "last_15m"),
+					resource.TestCheckResourceAttr(
+						"datadog_monitor.foo", "threshold_windows.recovery_window", "last_30m"),
+					func(s *terraform.State) error {
+						id, _ = strconv.Atoi(s.RootModule().Resources["datadog_monitor.foo"].Primary.ID)
+						return nil
+					},
+				),
+			},
+			resource.TestStep{
+				// Options set back to their zero value are sent, not left out.
+				Config: testAccCheckDatadogMonitorConfigOptionsDefaults,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"datadog_monitor.foo", "require_full_window", "true"),
+					resource.TestCheckResourceAttr(
+						"datadog_monitor.foo", "new_host_delay", "300"),
+					resource.TestCheckResourceAttr(
+						"datadog_monitor.foo", "locked", "false"),
+					resource.TestCheckResourceAttr(
+						"datadog_monitor.foo", "notify_by.#", "0"),
+					func(s *terraform.State) error {
+						if testAccFake == nil {
+							return nil
+						}
+						options := testAccFake.Monitor(id)["options"].(map[string]interface{})
+						expected := map[string]string{
+							"locked": "false", "evaluation_delay": "0", "include_tags": "true", "notify_by": "[]",
+							"notify_no_data": "false", "notify_audit": "false", "renotify_interval": "0",
+							"timeout_h": "0", "escalation_message": "", "silenced": "map[]"}
+						for k, v := range expected {
+							if o := fmt.Sprint(options[k]); o != v {
+								return fmt.Errorf("Expected option %s to be sent as %s, got %s", k, v, o)
+							}
+						}
+						return nil
+					},
+				),
+			},
+			resource.TestStep{
+				// Options Datadog leaves out of a response have their default value.
+				PreConfig: func() {
+					if testAccFake != nil {
+						testAccFake.UpdateMonitor(id, map[string]interface{}{
+							"options": map[string]interface{}{"notify_no_data": false}})
+					}
+				},
+				Config: testAccCheckDatadogMonitorConfigOptionsDefaults,
+			},
+		},
+	})
+}
+
+func TestAccDatadogMonitor_Metadata(t *testing.T) {
+	var id int
+	var modified string
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDatadogMonitorDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckDatadogMonitorConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDatadogMonitorExists("datadog_monitor.foo"),
+					testAccCheckDatadogMonitorAttrsSet("datadog_monitor.foo",
+						"overall_state", "creator_handle", "creator_email", "created", "modified"),
+					resource.TestCheckResourceAttr(
+						"datadog_monitor.foo", "deleted", ""),
+					func(s *terraform.State) error {
+						r := s.RootModule().Resources["datadog_monitor.foo"].Primary
+						id, _ = strconv.Atoi(r.ID)
+						modified = r.Attributes["modified"]
+						return nil
+					},
+				),
+			},
+			resource.TestStep{
+				// A monitor edited by hand shows a new modified time.
+				PreConfig: func() {
+					if testAccFake != nil {
+						testAccFake.UpdateMonitor(id, map[string]interface{}{
+							"overall_state": "Alert", "modified": "2030-01-02T15:04:05.000000+00:00"})
+					}
+				},
+				Config: testAccCheckDatadogMonitorConfig,
+				Check: func(s *terraform.State) error {
+					if testAccFake == nil {
+						return nil
+					}
+					r := s.RootModule().Resources["datadog_monitor.foo"].Primary
+					if r.Attributes["overall_state"] != "Alert" {
+						return fmt.Errorf("Expected overall_state Alert, got %q", r.Attributes["overall_state"])
+					}
+					if r.Attributes["modified"] == modified {
+						return fmt.Errorf("Expected modified to change from %s", modified)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func TestAccDatadogMonitor_DefaultTags(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDatadogMonitorDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckDatadogMonitorConfigDefaultTags,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDatadogMonitorExists("datadog_monitor.foo"),
+					resource.TestCheckResourceAttr(
+						"datadog_monitor.foo", "tags.#", "2"),
+					testAccCheckDatadogMonitorTags("datadog_monitor.foo", "team:foo", "env:dev", "foo:bar"),
+					testAccCheckDatadogMonitorTags("datadog_service_check.bar", "team:foo", "env:test"),
+				),
+			},
+		},
+	})
+}
+
+func TestResourceDatadogMonitor_Validate(t *testing.T) {
+	cases := []struct {
+		Name   string
+		Config map[string]interface{}
+		Errors int
+	}{
+		{
+			"valid",
+			map[string]interface{}{"type": "metric alert",
+				"thresholds": []map[string]interface{}{{"warning": 1, "critical": "2.5"}}},
+			0,
+		},
+		{
+			"unknown type",
+			map[string]interface{}{"type": "metric_alert",
+				"thresholds": []map[string]interface{}{{"critical": 2}}},
+			1,
+		},
+		{
+			"threshold that is not a number",
+			map[string]interface{}{"type": "metric alert",
+				"thresholds": []map[string]interface{}{{"warning": "high", "critical": 2}}},
+			1,
+		},
+		{
+			"unknown threshold",
+			map[string]interface{}{"type": "metric alert",
+				"thresholds": []map[string]interface{}{{"critcal": 2}}},
+			2,
+		},
+		{
+			"thresholds without critical",
+			map[string]interface{}{"type": "metric alert",
+				"thresholds": []map[string]interface{}{{"warning": 1}}},
+			1,
+		},
+		{
+			"composite without thresholds",
+			map[string]interface{}{"type": "composite"},
+			0,
+		},
+		{
+			"unknown outlier algorithm",
+			map[string]interface{}{"type": "query alert",
+				"query":      "avg(last_1h):outliers(avg:system.load.5{*} by {host}, 'kmeans', 3) > 0",
+				"thresholds": []map[string]interface{}{{"critical": 0}}},
+			1,
+		},
+		{
+			"outlier algorithm in a query being computed",
+			map[string]interface{}{"type": "query alert",
+				"query":      "avg(last_1h):outliers(avg:system.load.5{*} by {host}, '${var.algorithm}', 3) > 0",
+				"thresholds": []map[string]interface{}{{"critical": 0}}},
+			0,
+		},
+		{
+			"silenced until a timestamp",
+			map[string]interface{}{"type": "metric alert",
+				"thresholds": []map[string]interface{}{{"critical": 2}},
+				"silenced":   []map[string]interface{}{{"*": 0, "host:foo": "1467158400"}}},
+			0,
+		},
+		{
+			"silenced until a date",
+			map[string]interface{}{"type": "metric alert",
+				"thresholds": []map[string]interface{}{{"critical": 2}},
+				"silenced":   []map[string]interface{}{{"*": "2016-06-29"}}},
+			1,
+		},
+		{
+			"threshold windows",
+			map[string]interface{}{"type": "query alert",
+				"query":      "avg(last_1h):anomalies(avg:system.load.5{*}, 'basic', 2) >= 1",
+				"thresholds": []map[string]interface{}{{"critical": 1}},
+				"threshold_windows": []map[string]interface{}{
+					{"trigger_window": "last_15m", "recovery_window": "last_30m"}}},
+			0,
+		},
+		{
+			"misspelt threshold window",
+			map[string]interface{}{"type": "query alert",
+				"query":             "avg(last_1h):anomalies(avg:system.load.5{*}, 'basic', 2) >= 1",
+				"thresholds":        []map[string]interface{}{{"critical": 1}},
+				"threshold_windows": []map[string]interface{}{{"trigger_windw": "last_15m"}}},
+			1,
+		},
+	}
+
+	for _, tc := range cases {
+		tc.Config["name"] = "foo"
+		tc.Config["message"] = "bar"
+		if _, ok := tc.Config["query"]; !ok {
+			tc.Config["query"] = "avg(last_1h):avg:aws.ec2.cpu{*} > 2.5"
+		}
+		raw, err := config.NewRawConfig(tc.Config)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		_, errs := resourceDatadogMonitor().Validate(terraform.NewResourceConfig(raw))
+		if len(errs) != tc.Errors {
+			t.Fatalf("%s: expected %d errors, got %v", tc.Name, tc.Errors, errs)
+		}
+	}
+}
+
+func TestCheckMonitor(t *testing.T) {
+	cases := []struct {
+		Name       string
+		Type       string
+		Query      string
+		Thresholds map[string]string
+		Err        string
+	}{
+		{"metric alert", "metric alert", "avg(last_1h):avg:aws.ec2.cpu{*} > 3",
+			map[string]string{"ok": "0", "warning": "1", "critical": "3.0"}, ""},
+		{"metric alert below", "metric alert", "avg(last_1h):avg:aws.ec2.cpu{*} <= 1",
+			map[string]string{"warning": "2", "critical": "1"}, ""},
+		{"warning above critical", "metric alert", "avg(last_1h):avg:aws.ec2.cpu{*} > 3",
+			map[string]string{"warning": "4", "critical": "3"},
+			"with > the warning threshold (4) must be below the critical threshold (3)"},
+		{"warning below critical", "query alert", "avg(last_1h):avg:aws.ec2.cpu{*} < 3",
+			map[string]string{"ok": "5", "warning": "2", "critical": "3"},
+			"with < the warning threshold (2) must be above the critical threshold (3)"},
+		{"ok above warning", "metric alert", "avg(last_1h):avg:aws.ec2.cpu{*} >= 3",
+			map[string]string{"ok": "2", "warning": "1", "critical": "3"},
+			"with >= the ok threshold (2) must be below the warning threshold (1)"},
+		{"equality is not ordered", "metric alert", "avg(last_1h):avg:aws.ec2.cpu{*} != 3",
+			map[string]string{"warning": "4", "critical": "3"}, ""},
+		{"critical differs from query", "metric alert", "avg(last_1h):avg:aws.ec2.cpu{*} > 3",
+			map[string]string{"critical": "4"},
+			"critical threshold 4 does not match the threshold in the query, 3"},
+		{"query without comparison", "metric alert", "avg(last_1h):avg:aws.ec2.cpu{*}",
+			map[string]string{"critical": "4"},
+			"metric alert monitors need a query ending in a comparison"},
+		{"service check counts", "service check", `"datadog.agent.up".over("*").last(4).count_by_status()`,
+			map[string]string{"ok": "1", "warning": "2", "critical": "4"}, ""},
+		{"event alert", "event alert", "events('sources:nagios status:error').rollup('count').last('1h') > 10",
+			map[string]string{"critical": "10"}, ""},
+		{"event alert with warning", "event alert", "events('sources:nagios').rollup('count').last('1h') > 10",
+			map[string]string{"warning": "5", "critical": "10"},
+			"event alert monitors do not take a warning threshold"},
+		{"outlier algorithm", "query alert", "avg(last_1h):outliers(avg:system.load.5{*} by {host}, 'dbscan', 3) > 0",
+			map[string]string{"critical": "0"}, ""},
+		{"anomaly threshold windows", "query alert",
+			"avg(last_4h):anomalies(avg:system.cpu.user{*}, 'basic', 2, direction='both') >= 1",
+			map[string]string{"critical": "1"}, ""},
+		{"metric alert without critical", "metric alert", "avg(last_1h):avg:aws.ec2.cpu{*} > 3",
+			map[string]string{}, "metric alert monitors need a critical threshold"},
+		{"composite", "composite", "123 && !(456 || 789)", map[string]string{}, ""},
+		{"composite with critical", "composite", "123 && 456", map[string]string{"critical": "1"},
+			"composite monitors do not take a critical threshold"},
+		{"composite over a metric", "composite", "avg(last_1h):avg:aws.ec2.cpu{*} > 3", map[string]string{},
+			"composite monitors need a query combining monitor IDs"},
+	}
+
+	for _, tc := range cases {
+		d := resourceDatadogMonitor().TestResourceData()
+		d.Set("type", tc.Type)
+		d.Set("query", tc.Query)
+		d.Set("thresholds", tc.Thresholds)
+		if strings.Contains(tc.Query, "anomalies(") {
+			d.Set("threshold_windows", map[string]string{"trigger_window": "last_15m"})
+		}
+
+		err := checkMonitor(d)
+		if tc.Err == "" && err != nil {
+			t.Fatalf("%s: expected no error, got %s", tc.Name, err)
+		}
+		if tc.Err != "" && (err == nil || !strings.Contains(err.Error(), tc.Err)) {
+			t.Fatalf("%s: expected error %q, got %v", tc.Name, tc.Err, err)
+		}
+	}
+
+	d := resourceDatadogMonitor().TestResourceData()
+	d.Set("type", "metric alert")
+	d.Set("query", "avg(last_1h):avg:aws.ec2.cpu{*} > 3")
+	d.Set("thresholds", map[string]string{"critical": "3"})
+	d.Set("threshold_windows", map[string]string{"trigger_window": "last_15m"})
+	if err := checkMonitor(d); err == nil || !strings.Contains(err.Error(), "only apply to anomaly monitors") {
+		t.Fatalf("Expected threshold windows to be refused outside anomaly monitors, got %v", err)
+	}
+}
+
+func TestAccDatadogMonitor_Composite(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDatadogMonitorDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckDatadogMonitorConfigComposite,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDatadogMonitorExists("datadog_monitor.both"),
+					resource.TestCheckResourceAttr(
+						"datadog_monitor.both", "type", "composite"),
+					testAccCheckDatadogMonitorComposite("datadog_monitor.both", "%s && %s",
+						"datadog_monitor.errors", "datadog_monitor.latency"),
+				),
+			},
+			resource.TestStep{
+				Config: testAccCheckDatadogMonitorConfigCompositeUpdated,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDatadogMonitorComposite("datadog_monitor.both", "%s || !%s",
+						"datadog_monitor.errors", "datadog_monitor.latency"),
+				),
+			},
+		},
+	})
+}
+
+func TestMonitorDelete_UsedByComposite(t *testing.T) {
+	if testAccFake == nil {
+		t.Skip("Deleting a monitor used by a composite needs the fake API, TF_ACC is set")
+	}
+
+	meta := testAccFake.Meta(t)
+	client := meta.client
+	errorRate, err := client.CreateMonitor(&datadog.Monitor{Name: "errors", Type: "metric alert",
+		Query: "avg(last_5m):sum:app.errors{*} > 10"})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer testAccFake.DeleteMonitor(errorRate.Id)
+	both, err := client.CreateMonitor(&datadog.Monitor{Name: "both", Type: "composite",
+		Query: fmt.Sprintf("%d && 999", errorRate.Id)})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer testAccFake.DeleteMonitor(both.Id)
+
+	d := resourceDatadogMonitor().TestResourceData()
+	d.SetId(strconv.Itoa(errorRate.Id))
+	err = resourceDatadogGenericDelete(d, meta)
+	expected := fmt.Sprintf("monitor %d is used by composite monitors %d (\"both\")", errorRate.Id, both.Id)
+	if err == nil || !strings.Contains(err.Error(), expected) {
+		t.Fatalf("Expected an error naming the composite monitor, got %v", err)
+	}
+	if _, err := client.GetMonitor(errorRate.Id); err != nil {
+		t.Fatalf("Expected monitor %d to still exist, got %s", errorRate.Id, err)
+	}
+}
+
 func testAccCheckDatadogMonitorDestroy(s *terraform.State) error {
-	client := testAccProvider.Meta().(*datadog.Client)
+	client := testAccProvider.Meta().(*providerMeta).client
 
 	if err := destroyHelper(s, client); err != nil {
 		return err
@@ -127,9 +684,72 @@ func testAccCheckDatadogMonitorDestroy(s *terraform.State) error {
 	return nil
 }
 
+// testAccCheckDatadogMonitorTags checks the monitor behind resource n has
+// exactly the given tags, in any order, besides the marker of its create.
+func testAccCheckDatadogMonitorTags(n string, tags ...string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*providerMeta).client
+		i, _ := strconv.Atoi(s.RootModule().Resources[n].Primary.ID)
+		m, err := client.GetMonitor(i)
+		if err != nil {
+			return fmt.Errorf("Received an error retrieving monitor %s", err)
+		}
+
+		got := []string{}
+		for _, t := range m.Tags {
+			if !strings.HasPrefix(t, createMarkerPrefix) {
+				got = append(got, t)
+			}
+		}
+		want := append([]string{}, tags...)
+		sort.Strings(got)
+		sort.Strings(want)
+		if !reflect.DeepEqual(got, want) {
+			return fmt.Errorf("Expected monitor %d to have tags %v, got %v", i, want, got)
+		}
+		return nil
+	}
+}
+
+// testAccCheckDatadogMonitorComposite checks the query of composite monitor n
+// is format filled in with the IDs of the monitors behind refs.
+func testAccCheckDatadogMonitorComposite(n, format string, refs ...string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*providerMeta).client
+		ids := make([]interface{}, len(refs))
+		for i, ref := range refs {
+			ids[i] = s.RootModule().Resources[ref].Primary.ID
+		}
+		i, _ := strconv.Atoi(s.RootModule().Resources[n].Primary.ID)
+		m, err := client.GetMonitor(i)
+		if err != nil {
+			return fmt.Errorf("Received an error retrieving monitor %s", err)
+		}
+
+		if expected := fmt.Sprintf(format, ids...); m.Query != expected {
+			return fmt.Errorf("Expected monitor %d to have query %q, got %q", i, expected, m.Query)
+		}
+		return nil
+	}
+}
+
+// testAccCheckDatadogMonitorAttrsSet checks resource n has a value for each
+// of keys.
+func testAccCheckDatadogMonitorAttrsSet(n string, keys ...string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		r := s.RootModule().Resources[n].Primary
+		for _, k := range keys {
+			if r.Attributes[k] == "" {
+				return fmt.Errorf("Expected %s of %s to be set", k, n)
+			}
+		}
+		return nil
+	}
+}
+
 func testAccCheckDatadogMonitorExists(n string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*datadog.Client)
+		client := testAccProvider.Meta().(*providerMeta).client
 		if err := existsHelper(s, client); err != nil {
 			return err
 		}
@@ -188,3 +808,191 @@ resource "datadog_monitor" "foo" {
   }
 }
 `
+
+const testAccCheckDatadogMonitorConfigDefaultTags = `
+provider "datadog" {
+  default_tags = ["team:foo", "env:test"]
+}
+
+resource "datadog_monitor" "foo" {
+  name = "name for monitor foo"
+  type = "metric alert"
+  message = "some message Notify: @hipchat-channel"
+
+  query = "avg(last_1h):avg:aws.ec2.cpu{environment:foo,host:foo} by {host} > 2"
+
+  thresholds {
+	critical = 2
+  }
+
+  notify_no_data = false
+  tags = ["foo:bar", "env:dev"]
+}
+
+resource "datadog_service_check" "bar" {
+  name = "name for service check bar"
+  message = "some message Notify: @hipchat-channel"
+  check = "datadog.agent.up"
+
+  thresholds {
+	critical = 2
+  }
+}
+`
+
+const testAccCheckDatadogMonitorConfigTags = `
+resource "datadog_monitor" "foo" {
+  name = "name for monitor foo"
+  type = "metric alert"
+  message = "some message Notify: @hipchat-channel"
+  escalation_message = "the situation has escalated @pagerduty"
+
+  query = "avg(last_1h):avg:aws.ec2.cpu{environment:foo,host:foo} by {host} > 2"
+
+  thresholds {
+	ok = 0
+	warning = 1
+	critical = 2
+  }
+
+  notify_no_data = false
+  renotify_interval = 60
+
+  notify_audit = false
+  timeout_h = 60
+  include_tags = true
+  tags = ["foo:bar", "baz"]
+}
+`
+
+const testAccCheckDatadogMonitorConfigThresholdFormatting = `
+resource "datadog_monitor" "foo" {
+  name = "name for monitor foo"
+  type = "metric alert"
+  message = "some message Notify: @hipchat-channel"
+
+  query = "avg(last_1h):avg:aws.ec2.cpu{environment:foo,host:foo} by {host} > 2"
+
+  thresholds {
+	ok = "0"
+	warning = "1e0"
+	critical = "2.0"
+  }
+
+  notify_no_data = false
+}
+`
+
+const testAccCheckDatadogMonitorConfigOptions = `
+resource "datadog_monitor" "foo" {
+  name = "name for monitor foo"
+  type = "query alert"
+  message = "some message Notify: @hipchat-channel"
+
+  query = "avg(last_4h):anomalies(avg:system.cpu.user{*} by {host}, 'basic', 2) >= 1"
+
+  thresholds {
+	critical = 1
+  }
+  threshold_windows {
+	trigger_window = "last_15m"
+	recovery_window = "last_30m"
+  }
+
+  notify_no_data = false
+  notify_audit = true
+  renotify_interval = 60
+  timeout_h = 2
+  escalation_message = "still failing"
+  require_full_window = false
+  new_host_delay = 600
+  evaluation_delay = 900
+  locked = true
+  include_tags = false
+  notify_by = ["host"]
+}
+`
+
+const testAccCheckDatadogMonitorConfigOptionsDefaults = `
+resource "datadog_monitor" "foo" {
+  name = "name for monitor foo"
+  type = "query alert"
+  message = "some message Notify: @hipchat-channel"
+
+  query = "avg(last_4h):anomalies(avg:system.cpu.user{*} by {host}, 'basic', 2) >= 1"
+
+  thresholds {
+	critical = 1
+  }
+
+  notify_no_data = false
+}
+`
+
+const testAccCheckDatadogMonitorConfigComposite = `
+resource "datadog_monitor" "errors" {
+  name = "error rate"
+  type = "metric alert"
+  message = "some message Notify: @hipchat-channel"
+
+  query = "avg(last_5m):sum:app.errors{*} > 10"
+
+  thresholds {
+	critical = 10
+  }
+}
+
+resource "datadog_monitor" "latency" {
+  name = "latency"
+  type = "metric alert"
+  message = "some message Notify: @hipchat-channel"
+
+  query = "avg(last_5m):avg:app.latency{*} > 0.5"
+
+  thresholds {
+	critical = 0.5
+  }
+}
+
+resource "datadog_monitor" "both" {
+  name = "error rate and latency"
+  type = "composite"
+  message = "some message Notify: @hipchat-channel"
+
+  query = "${datadog_monitor.errors.id} && ${datadog_monitor.latency.id}"
+}
+`
+
+const testAccCheckDatadogMonitorConfigCompositeUpdated = `
+resource "datadog_monitor" "errors" {
+  name = "error rate"
+  type = "metric alert"
+  message = "some message Notify: @hipchat-channel"
+
+  query = "avg(last_5m):sum:app.errors{*} > 10"
+
+  thresholds {
+	critical = 10
+  }
+}
+
+resource "datadog_monitor" "latency" {
+  name = "latency"
+  type = "metric alert"
+  message = "some message Notify: @hipchat-channel"
+
+  query = "avg(last_5m):avg:app.latency{*} > 0.5"
+
+  thresholds {
+	critical = 0.5
+  }
+}
+
+resource "datadog_monitor" "both" {
+  name = "error rate or latency"
+  type = "composite"
+  message = "some message Notify: @hipchat-channel"
+
+  query = "${datadog_monitor.errors.id} || !${datadog_monitor.latency.id}"
+}
+`
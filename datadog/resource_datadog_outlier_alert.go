@@ -1,11 +1,11 @@
 package datadog
 
 import (
-	"bytes"
 	"fmt"
 	"log"
 
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/ojongerius/terraform-provider-datadog/query"
 	"github.com/zorkian/go-datadog-api"
 )
 
@@ -13,11 +13,14 @@ import (
 func resourceDatadogOutlierAlert() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceDatadogOutlierAlertCreate,
-		Read:   resourceDatadogGenericRead,
+		Read:   resourceDatadogOutlierAlertRead,
 		Update: resourceDatadogOutlierAlertUpdate,
-		Delete: resourceDatadogGenericDelete,
+		Delete: resourceDatadogLegacyMonitorDelete,
 		Exists: resourceDatadogGenericExists,
 
+		SchemaVersion: monitorSchemaVersion,
+		MigrateState:  migrateMonitorState,
+
 		Schema: map[string]*schema.Schema{
 			"name": &schema.Schema{
 				Type:     schema.TypeString,
@@ -54,8 +57,10 @@ func resourceDatadogOutlierAlert() *schema.Resource {
 				Required: true,
 			},
 			"threshold": &schema.Schema{
-				Type:     schema.TypeString,
-				Required: true,
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateNumber,
+				StateFunc:    normalizeNumberState,
 			},
 			// Additional Settings
 			"notify_no_data": &schema.Schema{
@@ -70,9 +75,10 @@ func resourceDatadogOutlierAlert() *schema.Resource {
 			},
 
 			"algorithm": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				Default:  "dbscan",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "dbscan",
+				ValidateFunc: validateOutlierAlgorithm,
 			},
 
 			"renotify_interval": &schema.Schema{
@@ -80,80 +86,52 @@ func resourceDatadogOutlierAlert() *schema.Resource {
 				Optional: true,
 				Default:  0,
 			},
+
+			// Read only, set when a pre 0.0.4 ID is migrated
+			"legacy_monitor_ids": legacyMonitorIDsSchema(),
 		},
 	}
 }
 
-// buildMonitorStruct returns a monitor struct
-func buildOutlierAlertStruct(d *schema.ResourceData) *datadog.Monitor {
-	name := d.Get("name").(string)
-	message := d.Get("message").(string)
-	timeAggr := d.Get("time_aggr").(string)
-	timeWindow := d.Get("time_window").(string)
-	spaceAggr := d.Get("space_aggr").(string)
-	metric := d.Get("metric").(string)
-	algorithm := d.Get("algorithm").(string)
-
-	// Tags are are no separate resource/gettable, so some trickery is needed
-	var buffer bytes.Buffer
-	if raw, ok := d.GetOk("tags"); ok {
-		list := raw.([]interface{})
-		length := (len(list) - 1)
-		for i, v := range list {
-			if length > 1 && v == "*" {
-				log.Print(fmt.Sprintf("[DEBUG] found wildcard, this is not supported for this type: %s", v))
-				continue
-			}
-			buffer.WriteString(fmt.Sprintf("%s", v))
-			if i != length {
-				buffer.WriteString(",")
-			}
-
-		}
-	}
+// outlierAlgorithms are the algorithms Datadog detects outliers with.
+var outlierAlgorithms = map[string]bool{
+	"dbscan": true,
+	"mad":    true,
+}
 
-	tagsParsed := buffer.String()
-
-	// Keys are used for multi alerts
-	var b bytes.Buffer
-	if raw, ok := d.GetOk("keys"); ok {
-		list := raw.([]interface{})
-		b.WriteString("by {")
-		length := (len(list) - 1)
-		for i, v := range list {
-			b.WriteString(fmt.Sprintf("%s", v))
-			if i != length {
-				b.WriteString(",")
-			}
-
-		}
-		b.WriteString("}")
+func validateOutlierAlgorithm(v interface{}, k string) (ws []string, es []error) {
+	if !outlierAlgorithms[v.(string)] {
+		es = append(es, fmt.Errorf("%q must be dbscan or mad, got %q", k, v))
 	}
+	return
+}
 
-	keys := b.String()
-
-	query := fmt.Sprintf("%s(%s):outliers(%s:%s{%s} %s, '%s',%s) > 0", timeAggr,
-		timeWindow,
-		spaceAggr,
-		metric,
-		tagsParsed,
-		keys,
-		algorithm,
-		d.Get("threshold"))
+// buildOutlierAlertQuery returns the query described by d.
+func buildOutlierAlertQuery(d *schema.ResourceData) *query.Outlier {
+	return &query.Outlier{
+		TimeAggr:   d.Get("time_aggr").(string),
+		TimeWindow: d.Get("time_window").(string),
+		SpaceAggr:  d.Get("space_aggr").(string),
+		Metric:     d.Get("metric").(string),
+		Scope:      getStringList(d, "tags"),
+		By:         getStringList(d, "keys"),
+		Algorithm:  d.Get("algorithm").(string),
+		Tolerance:  normalizeNumber(d.Get("threshold").(string)),
+	}
+}
 
-	log.Print(fmt.Sprintf("[DEBUG] submitting query: %s", query))
+// buildOutlierAlertStruct returns a monitor struct
+func buildOutlierAlertStruct(d *schema.ResourceData) *datadog.Monitor {
+	q := buildOutlierAlertQuery(d).String()
+	log.Printf("[DEBUG] submitting query: %s", q)
 
-	o := datadog.Options{
-		NotifyNoData:     d.Get("notify_no_data").(bool),
-		NoDataTimeframe:  d.Get("no_data_timeframe").(int),
-		RenotifyInterval: d.Get("renotify_interval").(int),
-	}
+	o := buildMonitorOptions(d)
 
 	m := datadog.Monitor{
 		Type:    "query alert",
-		Query:   query,
-		Name:    name,
-		Message: message,
+		Query:   q,
+		Name:    d.Get("name").(string),
+		Message: d.Get("message").(string),
 		Options: o,
 	}
 
@@ -167,6 +145,36 @@ func resourceDatadogOutlierAlertCreate(d *schema.ResourceData, meta interface{})
 	if err := monitorCreator(d, meta, m); err != nil {
 		return err
 	}
+	readLegacyMonitorIDs(d)
+
+	return nil
+}
+
+// resourceDatadogOutlierAlertRead syncs the state with the monitor in Datadog.
+func resourceDatadogOutlierAlertRead(d *schema.ResourceData, meta interface{}) error {
+	m, err := readMonitor(d, meta)
+	if err != nil || m == nil {
+		return err
+	}
+
+	q, err := query.ParseOutlier(m.Query)
+	if err != nil {
+		return fmt.Errorf("monitor %d has a query that cannot be expressed with metric, tags, keys, time_aggr, "+
+			"time_window, space_aggr, algorithm and threshold, manage it with datadog_monitor instead: %s", m.Id, err)
+	}
+	if !query.Equal(q, buildOutlierAlertQuery(d)) {
+		d.Set("time_aggr", q.TimeAggr)
+		d.Set("time_window", q.TimeWindow)
+		d.Set("space_aggr", q.SpaceAggr)
+		d.Set("metric", q.Metric)
+		readScope(d, q.Scope)
+		d.Set("keys", q.By)
+		d.Set("algorithm", q.Algorithm)
+		d.Set("threshold", normalizeNumber(q.Tolerance))
+	}
+
+	readMonitorOptions(d, resourceDatadogOutlierAlert().Schema, m)
+	readLegacyMonitorIDs(d)
 
 	return nil
 }
@@ -176,7 +184,7 @@ func resourceDatadogOutlierAlertUpdate(d *schema.ResourceData, meta interface{})
 	log.Printf("[DEBUG] running update.")
 
 	m := buildOutlierAlertStruct(d)
-	if err := monitorUpdater(d, meta, m); err != nil {
+	if err := monitorUpdater(d, meta, m, nil); err != nil {
 		return err
 	}
 
@@ -5,7 +5,6 @@ import (
 
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/terraform"
-	"github.com/zorkian/go-datadog-api"
 )
 
 func TestAccDatadogOutlierAlert_Basic(t *testing.T) {
@@ -55,7 +54,7 @@ func TestAccDatadogOutlierAlert_Basic(t *testing.T) {
 }
 
 func testAccCheckDatadogOutlierAlertDestroy(s *terraform.State) error {
-	client := testAccProvider.Meta().(*datadog.Client)
+	client := testAccProvider.Meta().(*providerMeta).client
 
 	if err := destroyHelper(s, client); err != nil {
 		return err
@@ -65,7 +64,7 @@ func testAccCheckDatadogOutlierAlertDestroy(s *terraform.State) error {
 
 func testAccCheckDatadogOutlierAlertExists(n string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*datadog.Client)
+		client := testAccProvider.Meta().(*providerMeta).client
 		if err := existsHelper(s, client); err != nil {
 			return err
 		}
@@ -0,0 +1,684 @@
+package datadog
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/zorkian/go-datadog-api"
+)
+
+// resourceDatadogScreenboard is a Datadog screenboard resource, a dashboard
+// of widgets placed freely on a grid. Each widget type has its own block.
+func resourceDatadogScreenboard() *schema.Resource {
+	s := map[string]*schema.Schema{
+		"title": &schema.Schema{
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"height": &schema.Schema{
+			Type:     schema.TypeString,
+			Optional: true,
+			Computed: true,
+		},
+		"width": &schema.Schema{
+			Type:     schema.TypeString,
+			Optional: true,
+			Computed: true,
+		},
+		// A shared screenboard has a public URL anyone can view it at, such
+		// as a status TV.
+		"shared": &schema.Schema{
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  false,
+		},
+		"template_variable": &schema.Schema{
+			Type:     schema.TypeList,
+			Optional: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"name": &schema.Schema{
+						Type:     schema.TypeString,
+						Required: true,
+					},
+					"prefix": &schema.Schema{
+						Type:     schema.TypeString,
+						Optional: true,
+					},
+					"default": &schema.Schema{
+						Type:     schema.TypeString,
+						Optional: true,
+					},
+				},
+			},
+		},
+
+		// Read only, set by Datadog
+		"public_url": &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+	}
+
+	for typ, attrs := range screenboardWidgets {
+		s[typ] = &schema.Schema{
+			Type:     schema.TypeList,
+			Optional: true,
+			Elem:     &schema.Resource{Schema: attrs},
+		}
+	}
+
+	return &schema.Resource{
+		Create: resourceDatadogScreenboardCreate,
+		Read:   resourceDatadogScreenboardRead,
+		Update: resourceDatadogScreenboardUpdate,
+		Delete: resourceDatadogScreenboardDelete,
+
+		Schema: s,
+	}
+}
+
+// screenboardWidgets are the attributes of each widget type. They are named
+// like the fields of the widget in the API, except that repeated blocks are
+// named in the singular, and their field is the plural. The blocks in
+// singleWidgetBlocks are single blocks.
+var screenboardWidgets = map[string]map[string]*schema.Schema{
+	"timeseries": widgetAttributes(true, map[string]*schema.Schema{
+		"timeframe": optionalAttribute(schema.TypeString),
+		"legend":    optionalAttribute(schema.TypeBool),
+		"tile_def":  tileDefSchema(),
+	}),
+	"query_value": widgetAttributes(true, map[string]*schema.Schema{
+		"timeframe":          optionalAttribute(schema.TypeString),
+		"aggr":               optionalAttribute(schema.TypeString),
+		"aggregator":         optionalAttribute(schema.TypeString),
+		"calc_func":          optionalAttribute(schema.TypeString),
+		"res_calc_func":      optionalAttribute(schema.TypeString),
+		"conditional_format": conditionalFormatSchema(),
+		"is_valid_query":     optionalAttribute(schema.TypeBool),
+		"metric":             optionalAttribute(schema.TypeString),
+		"metric_type":        optionalAttribute(schema.TypeString),
+		"precision":          optionalAttribute(schema.TypeInt),
+		"query":              optionalAttribute(schema.TypeString),
+		"tags":               optionalStringList(),
+		"text_align":         optionalAttribute(schema.TypeString),
+		"text_size":          optionalAttribute(schema.TypeString),
+		"unit":               optionalAttribute(schema.TypeString),
+	}),
+	"event_stream": widgetAttributes(true, map[string]*schema.Schema{
+		"query":      optionalAttribute(schema.TypeString),
+		"timeframe":  optionalAttribute(schema.TypeString),
+		"event_size": optionalAttribute(schema.TypeString),
+	}),
+	"free_text": widgetAttributes(false, map[string]*schema.Schema{
+		"text":       optionalAttribute(schema.TypeString),
+		"color":      optionalAttribute(schema.TypeString),
+		"font_size":  optionalAttribute(schema.TypeString),
+		"text_align": optionalAttribute(schema.TypeString),
+	}),
+	"toplist": widgetAttributes(true, map[string]*schema.Schema{
+		"timeframe":   optionalAttribute(schema.TypeString),
+		"legend":      optionalAttribute(schema.TypeBool),
+		"legend_size": optionalAttribute(schema.TypeInt),
+		"tile_def":    tileDefSchema(),
+	}),
+	"image": widgetAttributes(true, map[string]*schema.Schema{
+		"url":    optionalAttribute(schema.TypeString),
+		"sizing": optionalAttribute(schema.TypeString),
+	}),
+	"change": widgetAttributes(true, map[string]*schema.Schema{
+		"aggregator": optionalAttribute(schema.TypeString),
+		"tile_def":   tileDefSchema(),
+	}),
+	"graph": widgetAttributes(true, map[string]*schema.Schema{
+		"timeframe":   optionalAttribute(schema.TypeString),
+		"legend":      optionalAttribute(schema.TypeBool),
+		"legend_size": optionalAttribute(schema.TypeInt),
+		"tile_def":    tileDefSchema(),
+	}),
+	"event_timeline": widgetAttributes(true, map[string]*schema.Schema{
+		"query":     optionalAttribute(schema.TypeString),
+		"timeframe": optionalAttribute(schema.TypeString),
+	}),
+	"alert_value": widgetAttributes(true, map[string]*schema.Schema{
+		"alert_id":      optionalAttribute(schema.TypeInt),
+		"precision":     optionalAttribute(schema.TypeInt),
+		"text_align":    optionalAttribute(schema.TypeString),
+		"text_size":     optionalAttribute(schema.TypeString),
+		"unit":          optionalAttribute(schema.TypeString),
+		"timeframe":     optionalAttribute(schema.TypeString),
+		"add_timeframe": optionalAttribute(schema.TypeBool),
+	}),
+	"alert_graph": widgetAttributes(true, map[string]*schema.Schema{
+		"alert_id":      optionalAttribute(schema.TypeInt),
+		"viz_type":      optionalAttribute(schema.TypeString),
+		"timeframe":     optionalAttribute(schema.TypeString),
+		"add_timeframe": optionalAttribute(schema.TypeBool),
+	}),
+	"hostmap": widgetAttributes(true, map[string]*schema.Schema{
+		"query":       optionalAttribute(schema.TypeString),
+		"timeframe":   optionalAttribute(schema.TypeString),
+		"legend":      optionalAttribute(schema.TypeBool),
+		"legend_size": optionalAttribute(schema.TypeInt),
+		"tile_def":    tileDefSchema(),
+	}),
+	"check_status": widgetAttributes(true, map[string]*schema.Schema{
+		"check":      optionalAttribute(schema.TypeString),
+		"group":      optionalAttribute(schema.TypeString),
+		"grouping":   optionalAttribute(schema.TypeString),
+		"tags":       optionalStringList(),
+		"timeframe":  optionalAttribute(schema.TypeString),
+		"text_align": optionalAttribute(schema.TypeString),
+		"text_size":  optionalAttribute(schema.TypeString),
+	}),
+	"iframe": widgetAttributes(true, map[string]*schema.Schema{
+		"url": optionalAttribute(schema.TypeString),
+	}),
+	"note": widgetAttributes(true, map[string]*schema.Schema{
+		"html":          optionalAttribute(schema.TypeString),
+		"bgcolor":       optionalAttribute(schema.TypeString),
+		"font_size":     optionalAttribute(schema.TypeInt),
+		"text_align":    optionalAttribute(schema.TypeString),
+		"tick":          optionalAttribute(schema.TypeBool),
+		"tick_pos":      optionalAttribute(schema.TypeString),
+		"tick_edge":     optionalAttribute(schema.TypeString),
+		"auto_refresh":  optionalAttribute(schema.TypeBool),
+		"refresh_every": optionalAttribute(schema.TypeInt),
+	}),
+}
+
+// screenboardWidgetTypes are the widget types in the order they are sent.
+var screenboardWidgetTypes = func() []string {
+	var types []string
+	for typ := range screenboardWidgets {
+		types = append(types, typ)
+	}
+	sort.Strings(types)
+	return types
+}()
+
+// widgetAttributes adds the position and size every widget has to attrs, and
+// the title settings when titled is set.
+func widgetAttributes(titled bool, attrs map[string]*schema.Schema) map[string]*schema.Schema {
+	attrs["x"] = &schema.Schema{Type: schema.TypeInt, Required: true, ValidateFunc: validateNonNegativeInt}
+	attrs["y"] = &schema.Schema{Type: schema.TypeInt, Required: true, ValidateFunc: validateNonNegativeInt}
+	attrs["width"] = &schema.Schema{Type: schema.TypeInt, Required: true, ValidateFunc: validatePositiveInt}
+	attrs["height"] = &schema.Schema{Type: schema.TypeInt, Required: true, ValidateFunc: validatePositiveInt}
+
+	if titled {
+		attrs["title"] = optionalAttribute(schema.TypeBool)
+		attrs["title_text"] = optionalAttribute(schema.TypeString)
+		attrs["title_size"] = optionalAttribute(schema.TypeInt)
+		attrs["title_align"] = optionalAttribute(schema.TypeString)
+	}
+	return attrs
+}
+
+func optionalAttribute(t schema.ValueType) *schema.Schema {
+	return &schema.Schema{Type: t, Optional: true}
+}
+
+func optionalStringList() *schema.Schema {
+	return &schema.Schema{Type: schema.TypeList, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}}
+}
+
+func conditionalFormatSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"color":      optionalAttribute(schema.TypeString),
+				"comparator": optionalAttribute(schema.TypeString),
+				"invert":     optionalAttribute(schema.TypeBool),
+				"value":      optionalAttribute(schema.TypeFloat),
+			},
+		},
+	}
+}
+
+// tileDefSchema is the graph definition of a widget, a single block.
+func tileDefSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Required: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"viz": optionalAttribute(schema.TypeString),
+				"request": &schema.Schema{
+					Type:     schema.TypeList,
+					Optional: true,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"q":                  &schema.Schema{Type: schema.TypeString, Required: true},
+							"type":               optionalAttribute(schema.TypeString),
+							"conditional_format": conditionalFormatSchema(),
+							"style": &schema.Schema{
+								Type:     schema.TypeList,
+								Optional: true,
+								Elem: &schema.Resource{
+									Schema: map[string]*schema.Schema{
+										"palette": optionalAttribute(schema.TypeString),
+									},
+								},
+							},
+						},
+					},
+				},
+				"marker": &schema.Schema{
+					Type:     schema.TypeList,
+					Optional: true,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"type":  &schema.Schema{Type: schema.TypeString, Required: true},
+							"value": &schema.Schema{Type: schema.TypeString, Required: true},
+							"label": optionalAttribute(schema.TypeString),
+						},
+					},
+				},
+				"event": &schema.Schema{
+					Type:     schema.TypeList,
+					Optional: true,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"q": &schema.Schema{Type: schema.TypeString, Required: true},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// singleWidgetBlocks are the widget blocks that are a single object in the
+// API. They are blocks rather than maps, as helper/schema does not check the
+// attributes of a map.
+var singleWidgetBlocks = map[string]bool{"tile_def": true, "style": true}
+
+func validatePositiveInt(v interface{}, k string) (ws []string, es []error) {
+	if v.(int) < 1 {
+		es = append(es, fmt.Errorf("%q must be at least 1, got %d", k, v))
+	}
+	return
+}
+
+// widgetJSON returns the attributes v of a widget block with schema s in the
+// form the API takes them. Unset attributes are left out, as the API leaves
+// out settings that are not set.
+func widgetJSON(v map[string]interface{}, s map[string]*schema.Schema) (map[string]interface{}, error) {
+	out := make(map[string]interface{})
+	for k, sch := range s {
+		value := v[k]
+		if isZeroAttribute(value) {
+			continue
+		}
+
+		r, ok := sch.Elem.(*schema.Resource)
+		if sch.Type != schema.TypeList || !ok {
+			out[k] = value
+			continue
+		}
+
+		var blocks []interface{}
+		for _, b := range value.([]interface{}) {
+			m, err := widgetJSON(b.(map[string]interface{}), r.Schema)
+			if err != nil {
+				return nil, err
+			}
+			blocks = append(blocks, m)
+		}
+		if singleWidgetBlocks[k] {
+			if len(blocks) != 1 {
+				return nil, fmt.Errorf("takes one %s block, got %d", k, len(blocks))
+			}
+			out[k] = blocks[0]
+			continue
+		}
+		out[k+"s"] = blocks
+	}
+	return out, nil
+}
+
+// widgetState returns widget w, in the form the API sends it, as the
+// attributes of a widget block with schema s.
+func widgetState(w map[string]interface{}, s map[string]*schema.Schema) map[string]interface{} {
+	out := make(map[string]interface{})
+	for k, sch := range s {
+		r, ok := sch.Elem.(*schema.Resource)
+		switch {
+		case sch.Type == schema.TypeList && ok:
+			var blocks []interface{}
+			if singleWidgetBlocks[k] {
+				if b, ok := w[k]; ok {
+					blocks = append(blocks, b)
+				}
+			} else {
+				blocks, _ = w[k+"s"].([]interface{})
+			}
+			list := []map[string]interface{}{}
+			for _, b := range blocks {
+				list = append(list, widgetState(b.(map[string]interface{}), r.Schema))
+			}
+			out[k] = list
+		case sch.Type == schema.TypeInt:
+			f, _ := w[k].(float64)
+			out[k] = int(f)
+		default:
+			if value, ok := w[k]; ok {
+				out[k] = value
+			}
+		}
+	}
+	return out
+}
+
+// overlayWidget returns widget base, in the form the API sends it, with the
+// attributes of schema s set as they are in v, the form widgetJSON returns.
+// Settings of base that s does not cover, such as ones only the UI sets, are
+// kept. Blocks are overlaid onto the block at the same index of base.
+func overlayWidget(base, v map[string]interface{}, s map[string]*schema.Schema) map[string]interface{} {
+	out := make(map[string]interface{})
+	for k, value := range base {
+		out[k] = value
+	}
+
+	for k, sch := range s {
+		r, ok := sch.Elem.(*schema.Resource)
+		isBlock := sch.Type == schema.TypeList && ok
+		if isBlock && !singleWidgetBlocks[k] {
+			k += "s"
+		}
+
+		value, ok := v[k]
+		switch {
+		case !ok:
+			// Zero settings are left as Datadog has them, as the API does
+			// not always leave them out.
+			if !isZeroAttribute(out[k]) {
+				delete(out, k)
+			}
+		case !isBlock:
+			out[k] = value
+		case singleWidgetBlocks[k]:
+			b, _ := out[k].(map[string]interface{})
+			out[k] = overlayWidget(b, value.(map[string]interface{}), r.Schema)
+		default:
+			current, _ := out[k].([]interface{})
+			var blocks []interface{}
+			for i, block := range value.([]interface{}) {
+				var b map[string]interface{}
+				if i < len(current) {
+					b, _ = current[i].(map[string]interface{})
+				}
+				blocks = append(blocks, overlayWidget(b, block.(map[string]interface{}), r.Schema))
+			}
+			out[k] = blocks
+		}
+	}
+	return out
+}
+
+// widgetObject returns widget w in the form the API sends it.
+func widgetObject(w datadog.Widget) (map[string]interface{}, error) {
+	b, err := json.Marshal(w)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func isZeroAttribute(v interface{}) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case int:
+		return v == 0
+	case float64:
+		return v == 0
+	case bool:
+		return !v
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	}
+	return false
+}
+
+// buildScreenboardStruct returns the screenboard of d. Each widget is
+// overlaid onto the widget of the same type and index in current, the widgets
+// the board has in Datadog, so settings the resource does not manage are
+// kept. Widgets of current of a type the resource has no block for are kept
+// as they are.
+func buildScreenboardStruct(d *schema.ResourceData, current []datadog.Widget) (*datadog.Screenboard, error) {
+	board := datadog.Screenboard{
+		Title:             d.Get("title").(string),
+		Height:            d.Get("height").(string),
+		Width:             d.Get("width").(string),
+		Widgets:           []datadog.Widget{},
+		TemplateVariables: []datadog.TemplateVariable{},
+	}
+
+	bases := make(map[string][]map[string]interface{})
+	var unmanaged []datadog.Widget
+	for _, w := range current {
+		m, err := widgetObject(w)
+		if err != nil {
+			return nil, err
+		}
+		typ, _ := m["type"].(string)
+		if _, ok := screenboardWidgets[typ]; !ok {
+			unmanaged = append(unmanaged, w)
+			continue
+		}
+		bases[typ] = append(bases[typ], m)
+	}
+
+	for _, typ := range screenboardWidgetTypes {
+		for i, v := range d.Get(typ).([]interface{}) {
+			m, err := widgetJSON(v.(map[string]interface{}), screenboardWidgets[typ])
+			if err != nil {
+				return nil, fmt.Errorf("%s widget %d: %s", typ, i, err)
+			}
+
+			var base map[string]interface{}
+			if i < len(bases[typ]) {
+				base = bases[typ][i]
+			}
+			m = overlayWidget(base, m, screenboardWidgets[typ])
+			m["type"] = typ
+
+			b, err := json.Marshal(m)
+			if err != nil {
+				return nil, fmt.Errorf("%s widget %d: %s", typ, i, err)
+			}
+			board.Widgets = append(board.Widgets, datadog.Widget{Raw: b})
+		}
+	}
+	board.Widgets = append(board.Widgets, unmanaged...)
+
+	for i := 0; i < d.Get("template_variable.#").(int); i++ {
+		prefix := fmt.Sprintf("template_variable.%d.", i)
+		board.TemplateVariables = append(board.TemplateVariables, datadog.TemplateVariable{
+			Name:    d.Get(prefix + "name").(string),
+			Prefix:  d.Get(prefix + "prefix").(string),
+			Default: d.Get(prefix + "default").(string),
+		})
+	}
+	board.Templated = len(board.TemplateVariables) > 0
+
+	return &board, nil
+}
+
+// readScreenboard sets the attributes of d from board. Widgets are grouped by
+// type, in the order Datadog has them. Widgets of a type the resource has no
+// block for, such as ones added in the UI, are left out.
+func readScreenboard(d *schema.ResourceData, board *datadog.Screenboard) error {
+	widgets := make(map[string][]map[string]interface{})
+	for _, w := range board.Widgets {
+		m, err := widgetObject(w)
+		if err != nil {
+			return err
+		}
+		typ, _ := m["type"].(string)
+		if _, ok := screenboardWidgets[typ]; !ok {
+			log.Printf("[WARN] screenboard %d has a widget this provider does not manage, it is left as it is: %s",
+				board.Id, w.Raw)
+			continue
+		}
+		widgets[typ] = append(widgets[typ], widgetState(m, screenboardWidgets[typ]))
+	}
+
+	d.Set("title", board.Title)
+	d.Set("height", board.Height)
+	d.Set("width", board.Width)
+	for _, typ := range screenboardWidgetTypes {
+		d.Set(typ, widgets[typ])
+	}
+
+	variables := []map[string]interface{}{}
+	for _, v := range board.TemplateVariables {
+		variables = append(variables, map[string]interface{}{
+			"name": v.Name, "prefix": v.Prefix, "default": v.Default})
+	}
+	d.Set("template_variable", variables)
+
+	return nil
+}
+
+func resourceDatadogScreenboardCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*providerMeta).client
+
+	board, err := buildScreenboardStruct(d, nil)
+	if err != nil {
+		return err
+	}
+
+	created, err := client.CreateScreenboard(board)
+	if err != nil {
+		return fmt.Errorf("error creating screenboard: %s", err.Error())
+	}
+	d.SetId(strconv.Itoa(created.Id))
+
+	if d.Get("shared").(bool) {
+		if err := shareScreenboard(d, client, created.Id); err != nil {
+			return err
+		}
+	}
+
+	return resourceDatadogScreenboardRead(d, meta)
+}
+
+func resourceDatadogScreenboardRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*providerMeta).client
+
+	i, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return err
+	}
+
+	board, err := client.GetScreenboard(i)
+	if datadog.IsNotFound(err) {
+		log.Printf("[WARN] screenboard %d not found, removing from state", i)
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error reading screenboard %d: %s", i, err)
+	}
+
+	// The public URL is only known from sharing, which Read must not do. A
+	// board shared in the UI, or imported, keeps the URL it has in state.
+	d.Set("shared", board.Shared)
+	if !board.Shared {
+		d.Set("public_url", "")
+	}
+
+	return readScreenboard(d, board)
+}
+
+// shareScreenboard shares screenboard id and stores its public URL in d.
+func shareScreenboard(d *schema.ResourceData, client *datadog.Client, id int) error {
+	var share datadog.ScreenShareResponse
+	if err := client.ShareScreenboard(id, &share); err != nil {
+		return fmt.Errorf("error sharing screenboard %d: %s", id, err.Error())
+	}
+	d.Set("public_url", share.PublicUrl)
+	return nil
+}
+
+// resourceDatadogScreenboardUpdate replaces the screenboard with the one
+// configured, including its widgets. An update replaces the whole board, so
+// it is built onto the board Datadog has, which keeps the widgets and widget
+// settings the resource does not manage.
+func resourceDatadogScreenboardUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*providerMeta).client
+
+	i, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return err
+	}
+
+	current, err := client.GetScreenboard(i)
+	if err != nil {
+		return fmt.Errorf("error updating screenboard %d: %s", i, err.Error())
+	}
+
+	board, err := buildScreenboardStruct(d, current.Widgets)
+	if err != nil {
+		return err
+	}
+	board.Id = i
+
+	if err := client.UpdateScreenboard(board); err != nil {
+		return fmt.Errorf("error updating screenboard %d: %s", i, err.Error())
+	}
+
+	switch {
+	case !d.HasChange("shared"):
+	case d.Get("shared").(bool):
+		if err := shareScreenboard(d, client, i); err != nil {
+			return err
+		}
+	default:
+		if err := client.RevokeScreenboard(i); err != nil {
+			return fmt.Errorf("error revoking the share of screenboard %d: %s", i, err.Error())
+		}
+	}
+
+	return resourceDatadogScreenboardRead(d, meta)
+}
+
+func resourceDatadogScreenboardDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*providerMeta).client
+
+	i, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return err
+	}
+
+	// Revoke the share first, so the public URL stops working even if the
+	// delete fails.
+	if d.Get("shared").(bool) {
+		if err = client.RevokeScreenboard(i); err != nil && !datadog.IsNotFound(err) {
+			return fmt.Errorf("error revoking the share of screenboard %d: %s", i, err.Error())
+		}
+	}
+
+	// A screenboard that is already gone needs no deleting.
+	if err = client.DeleteScreenboard(i); err != nil && !datadog.IsNotFound(err) {
+		return fmt.Errorf("error deleting screenboard %d: %s", i, err.Error())
+	}
+
+	return nil
+}
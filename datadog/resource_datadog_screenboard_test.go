@@ -0,0 +1,733 @@
+package datadog
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/zorkian/go-datadog-api"
+)
+
+func TestAccDatadogScreenboard_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDatadogScreenboardDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckDatadogScreenboardConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDatadogScreenboardWidgets("datadog_screenboard.foo", 3),
+					resource.TestCheckResourceAttr(
+						"datadog_screenboard.foo", "title", "foo screenboard"),
+					resource.TestCheckResourceAttr(
+						"datadog_screenboard.foo", "timeseries.#", "1"),
+					resource.TestCheckResourceAttr(
+						"datadog_screenboard.foo", "timeseries.0.x", "1"),
+					resource.TestCheckResourceAttr(
+						"datadog_screenboard.foo", "timeseries.0.y", "2"),
+					resource.TestCheckResourceAttr(
+						"datadog_screenboard.foo", "timeseries.0.title_text", "CPU"),
+					resource.TestCheckResourceAttr(
+						"datadog_screenboard.foo", "timeseries.0.tile_def.0.request.0.q", "avg:system.cpu.user{$host}"),
+					resource.TestCheckResourceAttr(
+						"datadog_screenboard.foo", "timeseries.0.tile_def.0.request.0.style.0.palette", "warm"),
+					resource.TestCheckResourceAttr(
+						"datadog_screenboard.foo", "timeseries.0.tile_def.0.event.0.q", "sources:deploys"),
+					resource.TestCheckResourceAttr(
+						"datadog_screenboard.foo", "query_value.0.conditional_format.0.value", "90"),
+					resource.TestCheckResourceAttr(
+						"datadog_screenboard.foo", "note.0.html", "Owned by the web team"),
+					resource.TestCheckResourceAttr(
+						"datadog_screenboard.foo", "template_variable.0.name", "host"),
+				),
+			},
+			resource.TestStep{
+				Config: testAccCheckDatadogScreenboardConfigUpdated,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDatadogScreenboardWidgets("datadog_screenboard.foo", 2),
+					resource.TestCheckResourceAttr(
+						"datadog_screenboard.foo", "timeseries.0.width", "60"),
+					resource.TestCheckResourceAttr(
+						"datadog_screenboard.foo", "query_value.#", "0"),
+					resource.TestCheckResourceAttr(
+						"datadog_screenboard.foo", "iframe.0.url", "https://status.example.com"),
+					resource.TestCheckResourceAttr(
+						"datadog_screenboard.foo", "template_variable.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDatadogScreenboard_ChangedOutsideTerraform(t *testing.T) {
+	if testAccFake == nil {
+		t.Skip("Changing a screenboard outside Terraform needs the fake API, TF_ACC is set")
+	}
+
+	var id int
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDatadogScreenboardDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckDatadogScreenboardConfig,
+				Check: func(s *terraform.State) error {
+					id, _ = strconv.Atoi(s.RootModule().Resources["datadog_screenboard.foo"].Primary.ID)
+					return nil
+				},
+			},
+			resource.TestStep{
+				// A widget moved and given a legend in the UI is put back.
+				PreConfig: func() {
+					widgets := testAccFake.Screenboard(id)["widgets"].([]interface{})
+					for _, w := range widgets {
+						if w := w.(map[string]interface{}); w["type"] == "timeseries" {
+							w["x"] = 40
+							w["legend"] = true
+						}
+					}
+					testAccFake.UpdateScreenboard(id, map[string]interface{}{"widgets": widgets})
+				},
+				Config: testAccCheckDatadogScreenboardConfig,
+				Check: func(s *terraform.State) error {
+					for _, w := range testAccFake.Screenboard(id)["widgets"].([]interface{}) {
+						if w := w.(map[string]interface{}); w["type"] == "timeseries" {
+							if w["x"] != float64(1) || w["legend"] != nil {
+								return fmt.Errorf("Expected the timeseries widget to be put back, got %v", w)
+							}
+						}
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func TestAccDatadogScreenboard_UnmanagedWidget(t *testing.T) {
+	if testAccFake == nil {
+		t.Skip("Adding a widget outside Terraform needs the fake API, TF_ACC is set")
+	}
+
+	var id int
+	logStream := map[string]interface{}{"type": "log_stream", "x": 1, "y": 40, "query": "service:web"}
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDatadogScreenboardDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckDatadogScreenboardConfig,
+				Check: func(s *terraform.State) error {
+					id, _ = strconv.Atoi(s.RootModule().Resources["datadog_screenboard.foo"].Primary.ID)
+					return nil
+				},
+			},
+			resource.TestStep{
+				// A widget of a type the resource has no block for, and
+				// settings the resource does not manage, added in the UI,
+				// neither break the refresh nor show up as a change.
+				PreConfig: func() {
+					widgets := testAccFake.Screenboard(id)["widgets"].([]interface{})
+					for _, w := range widgets {
+						if w := w.(map[string]interface{}); w["type"] == "timeseries" {
+							w["time"] = map[string]interface{}{"live_span": "1h"}
+							w["tile_def"].(map[string]interface{})["autoscale"] = true
+						}
+					}
+					testAccFake.UpdateScreenboard(id, map[string]interface{}{"widgets": append(widgets, logStream)})
+				},
+				Config: testAccCheckDatadogScreenboardConfig,
+			},
+			resource.TestStep{
+				// Updating the board keeps them.
+				Config: testAccCheckDatadogScreenboardConfigUpdated,
+				Check: func(s *terraform.State) error {
+					kept := false
+					for _, w := range testAccFake.Screenboard(id)["widgets"].([]interface{}) {
+						switch w := w.(map[string]interface{}); w["type"] {
+						case "log_stream":
+							if w["query"] != "service:web" {
+								return fmt.Errorf("Expected the log stream widget to be kept as it was, got %v", w)
+							}
+							kept = true
+						case "timeseries":
+							tileDef := w["tile_def"].(map[string]interface{})
+							if w["width"] != float64(60) || w["time"] == nil || tileDef["autoscale"] != true {
+								return fmt.Errorf("Expected the timeseries widget to be updated with its UI settings kept, got %v", w)
+							}
+						}
+					}
+					if !kept {
+						return fmt.Errorf("Expected the log stream widget to be kept")
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func TestAccDatadogScreenboard_Shared(t *testing.T) {
+	var id int
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDatadogScreenboardDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckDatadogScreenboardConfigShared("status TV", true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDatadogScreenboardShared("datadog_screenboard.tv", true),
+					resource.TestCheckResourceAttr(
+						"datadog_screenboard.tv", "shared", "true"),
+					func(s *terraform.State) error {
+						id, _ = strconv.Atoi(s.RootModule().Resources["datadog_screenboard.tv"].Primary.ID)
+						return nil
+					},
+				),
+			},
+			resource.TestStep{
+				// A share revoked in the UI is shared again.
+				PreConfig: func() {
+					if testAccFake != nil {
+						testAccProvider.Meta().(*providerMeta).client.RevokeScreenboard(id)
+					}
+				},
+				Config: testAccCheckDatadogScreenboardConfigShared("status TV", true),
+				Check:  testAccCheckDatadogScreenboardShared("datadog_screenboard.tv", true),
+			},
+			resource.TestStep{
+				Config: testAccCheckDatadogScreenboardConfigShared("status TV", false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDatadogScreenboardShared("datadog_screenboard.tv", false),
+					resource.TestCheckResourceAttr(
+						"datadog_screenboard.tv", "public_url", ""),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDatadogScreenboard_SharedOnce(t *testing.T) {
+	if testAccFake == nil {
+		t.Skip("Counting screenboard shares needs the fake API, TF_ACC is set")
+	}
+
+	var id int
+	var url string
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDatadogScreenboardDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckDatadogScreenboardConfigShared("status TV", true),
+				Check: func(s *terraform.State) error {
+					r := s.RootModule().Resources["datadog_screenboard.tv"].Primary
+					id, _ = strconv.Atoi(r.ID)
+					url = r.Attributes["public_url"]
+					if n := testAccFake.Shares(id); n != 1 {
+						return fmt.Errorf("Expected screenboard %d to be shared once, got %d", id, n)
+					}
+					return nil
+				},
+			},
+			resource.TestStep{
+				// Updating a shared board does not share it again.
+				Config: testAccCheckDatadogScreenboardConfigShared("status TV, renamed", true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"datadog_screenboard.tv", "title", "status TV, renamed"),
+					func(s *terraform.State) error {
+						if n := testAccFake.Shares(id); n != 1 {
+							return fmt.Errorf("Expected screenboard %d to be shared once, got %d", id, n)
+						}
+						return resource.TestCheckResourceAttr("datadog_screenboard.tv", "public_url", url)(s)
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestScreenboardRead_DoesNotShare(t *testing.T) {
+	if testAccFake == nil {
+		t.Skip("Counting screenboard shares needs the fake API, TF_ACC is set")
+	}
+	meta := testAccFake.Meta(t)
+
+	d := resourceDatadogScreenboard().TestResourceData()
+	d.Set("title", "status TV")
+	if err := resourceDatadogScreenboardCreate(d, meta); err != nil {
+		t.Fatal(err)
+	}
+	id, _ := strconv.Atoi(d.Id())
+	defer resourceDatadogScreenboardDelete(d, meta)
+
+	// A board shared in the UI is read as shared, without a public URL.
+	testAccFake.UpdateScreenboard(id, map[string]interface{}{"shared": true})
+	if err := resourceDatadogScreenboardRead(d, meta); err != nil {
+		t.Fatal(err)
+	}
+	if !d.Get("shared").(bool) {
+		t.Fatalf("Expected screenboard %d to be read as shared", id)
+	}
+	if url := d.Get("public_url").(string); url != "" {
+		t.Fatalf("Expected no public_url, got %s", url)
+	}
+	if n := testAccFake.Shares(id); n != 0 {
+		t.Fatalf("Expected reading screenboard %d not to share it, got %d shares", id, n)
+	}
+}
+
+func TestScreenboardDelete_RevokesShare(t *testing.T) {
+	if testAccFake == nil {
+		t.Skip("Failing a screenboard delete needs the fake API, TF_ACC is set")
+	}
+	meta := testAccFake.Meta(t)
+
+	d := resourceDatadogScreenboard().TestResourceData()
+	d.Set("title", "status TV")
+	d.Set("shared", true)
+	if err := resourceDatadogScreenboardCreate(d, meta); err != nil {
+		t.Fatal(err)
+	}
+	id, _ := strconv.Atoi(d.Id())
+	if want := fmt.Sprintf("%s/sb/fake-%d", testAccFake.URL, id); d.Get("public_url") != want {
+		t.Fatalf("Expected public_url %s, got %s", want, d.Get("public_url"))
+	}
+
+	// The share is revoked even when the delete then fails.
+	testAccFake.Inject(&fakeFault{Method: "DELETE", Path: fmt.Sprintf("/api/v1/screen/%d", id), Status: 400, Count: 1})
+	if err := resourceDatadogScreenboardDelete(d, meta); err == nil {
+		t.Fatalf("Expected the injected delete failure")
+	}
+	if shared, _ := testAccFake.Screenboard(id)["shared"].(bool); shared {
+		t.Fatalf("Expected the share of screenboard %d to be revoked", id)
+	}
+
+	if err := resourceDatadogScreenboardDelete(d, meta); err != nil {
+		t.Fatal(err)
+	}
+	if testAccFake.Screenboard(id) != nil {
+		t.Fatalf("Expected screenboard %d to be deleted", id)
+	}
+}
+
+// TestScreenboardWidgetsRoundTrip reads a board with every widget type and
+// setting, as built in the UI, and checks the board built back from state is
+// the same as the JSON Datadog sent, including the settings the resource
+// does not manage.
+func TestScreenboardWidgetsRoundTrip(t *testing.T) {
+	var board datadog.Screenboard
+	if err := json.Unmarshal([]byte(testScreenboardJSON), &board); err != nil {
+		t.Fatal(err)
+	}
+	var raw struct {
+		Widgets []map[string]interface{} `json:"widgets"`
+	}
+	if err := json.Unmarshal([]byte(testScreenboardJSON), &raw); err != nil {
+		t.Fatal(err)
+	}
+	if types := len(raw.Widgets); types != len(screenboardWidgets) {
+		t.Fatalf("Expected the board to have one widget of each of the %d types, got %d", len(screenboardWidgets), types)
+	}
+
+	d := resourceDatadogScreenboard().TestResourceData()
+	if err := readScreenboard(d, &board); err != nil {
+		t.Fatal(err)
+	}
+	built, err := buildScreenboardStruct(d, board.Widgets)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := sortedObjects(t, raw.Widgets), sortedWidgets(t, built.Widgets); !reflect.DeepEqual(want, got) {
+		t.Fatalf("Expected widgets\n%s\ngot\n%s", want, got)
+	}
+	if !reflect.DeepEqual(board.TemplateVariables, built.TemplateVariables) || !built.Templated {
+		t.Fatalf("Expected template variables %v, got %v", board.TemplateVariables, built.TemplateVariables)
+	}
+
+	// Changing managed settings keeps the ones the resource does not manage.
+	timeseries := d.Get("timeseries").([]interface{})
+	widget := timeseries[0].(map[string]interface{})
+	widget["title_text"] = "User CPU"
+	request := widget["tile_def"].([]interface{})[0].(map[string]interface{})["request"].([]interface{})[0]
+	request.(map[string]interface{})["q"] = "max:system.cpu.user{$host}"
+	d.Set("timeseries", timeseries)
+
+	built, err = buildScreenboardStruct(d, board.Widgets)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := raw.Widgets[0]
+	expected["title_text"] = "User CPU"
+	tileDef := expected["tile_def"].(map[string]interface{})
+	tileDef["requests"].([]interface{})[0].(map[string]interface{})["q"] = "max:system.cpu.user{$host}"
+	if want, got := sortedObjects(t, raw.Widgets), sortedWidgets(t, built.Widgets); !reflect.DeepEqual(want, got) {
+		t.Fatalf("Expected widgets\n%s\ngot\n%s", want, got)
+	}
+}
+
+func TestScreenboardUnknownWidget(t *testing.T) {
+	data := `{"id": 1, "board_title": "foo", "widgets": [
+	  {"type": "note", "x": 1, "y": 1, "html": "hi"},
+	  {"type": "manage_status", "x": 1, "y": 20, "params": {"sort": "status,asc"}}]}`
+
+	var board datadog.Screenboard
+	if err := json.Unmarshal([]byte(data), &board); err != nil {
+		t.Fatalf("Expected a board with an unknown widget to be read, got %s", err)
+	}
+	if len(board.Widgets) != 2 || board.Widgets[1].Raw == nil {
+		t.Fatalf("Expected the unknown widget to be kept as sent, got %#v", board.Widgets)
+	}
+
+	b, err := json.Marshal(board.Widgets[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sent, expected interface{}
+	json.Unmarshal(b, &sent)
+	json.Unmarshal([]byte(`{"type": "manage_status", "x": 1, "y": 20, "params": {"sort": "status,asc"}}`), &expected)
+	if !reflect.DeepEqual(sent, expected) {
+		t.Fatalf("Expected the unknown widget to be sent back unchanged, got %s", b)
+	}
+
+	d := resourceDatadogScreenboard().TestResourceData()
+	if err := readScreenboard(d, &board); err != nil {
+		t.Fatal(err)
+	}
+	if n := d.Get("note.#"); n != 1 {
+		t.Fatalf("Expected the note to be read, got %v", n)
+	}
+}
+
+func TestScreenboardWidgetValidation(t *testing.T) {
+	cases := []struct {
+		Key   string
+		Value int
+		Err   bool
+	}{
+		{"x", 0, false},
+		{"x", -1, true},
+		{"y", -5, true},
+		{"width", 1, false},
+		{"width", 0, true},
+		{"height", 0, true},
+	}
+
+	for _, tc := range cases {
+		_, es := screenboardWidgets["note"][tc.Key].ValidateFunc(tc.Value, tc.Key)
+		if (len(es) > 0) != tc.Err {
+			t.Fatalf("%s = %d: expected an error: %t, got %v", tc.Key, tc.Value, tc.Err, es)
+		}
+	}
+
+	d := resourceDatadogScreenboard().TestResourceData()
+	d.Set("title", "two tile_defs")
+	d.Set("change", []map[string]interface{}{{
+		"x": 0, "y": 0, "width": 10, "height": 10,
+		"tile_def": []map[string]interface{}{{"viz": "change"}, {"viz": "change"}},
+	}})
+	if _, err := buildScreenboardStruct(d, nil); err == nil {
+		t.Fatalf("Expected an error for a widget with two tile_def blocks")
+	}
+
+	for name, style := range map[string]map[string]interface{}{
+		"valid":              {"palette": "warm"},
+		"misspelt attribute": {"palete": "warm"},
+	} {
+		raw, err := config.NewRawConfig(map[string]interface{}{
+			"title": "foo",
+			"timeseries": []map[string]interface{}{{
+				"x": 0, "y": 0, "width": 10, "height": 10,
+				"tile_def": []map[string]interface{}{{
+					"request": []map[string]interface{}{{
+						"q":     "avg:system.cpu.user{*}",
+						"style": []map[string]interface{}{style},
+					}},
+				}},
+			}},
+		})
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		_, errs := resourceDatadogScreenboard().Validate(terraform.NewResourceConfig(raw))
+		if (len(errs) > 0) != (name != "valid") {
+			t.Fatalf("%s style: unexpected errors %v", name, errs)
+		}
+	}
+}
+
+// sortedWidgets returns widgets as JSON, in a stable order.
+func sortedWidgets(t *testing.T, widgets []datadog.Widget) []string {
+	var objects []map[string]interface{}
+	for _, w := range widgets {
+		m, err := widgetObject(w)
+		if err != nil {
+			t.Fatal(err)
+		}
+		objects = append(objects, m)
+	}
+	return sortedObjects(t, objects)
+}
+
+// sortedObjects returns widgets, in the form the API sends them, as JSON in
+// a stable order.
+func sortedObjects(t *testing.T, widgets []map[string]interface{}) []string {
+	var list []string
+	for _, w := range widgets {
+		b, err := json.Marshal(w)
+		if err != nil {
+			t.Fatal(err)
+		}
+		list = append(list, string(b))
+	}
+	sort.Strings(list)
+	return list
+}
+
+// testAccCheckDatadogScreenboardWidgets checks screenboard n has count
+// widgets.
+func testAccCheckDatadogScreenboardWidgets(n string, count int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*providerMeta).client
+		i, _ := strconv.Atoi(s.RootModule().Resources[n].Primary.ID)
+		board, err := client.GetScreenboard(i)
+		if err != nil {
+			return fmt.Errorf("Received an error retrieving screenboard %s", err)
+		}
+		if len(board.Widgets) != count {
+			return fmt.Errorf("Expected screenboard %d to have %d widgets, got %d", i, count, len(board.Widgets))
+		}
+		return nil
+	}
+}
+
+// testAccCheckDatadogScreenboardShared checks screenboard n is shared, and
+// has a public URL, or is not.
+func testAccCheckDatadogScreenboardShared(n string, shared bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*providerMeta).client
+		r := s.RootModule().Resources[n].Primary
+		i, _ := strconv.Atoi(r.ID)
+		board, err := client.GetScreenboard(i)
+		if err != nil {
+			return fmt.Errorf("Received an error retrieving screenboard %s", err)
+		}
+		if board.Shared != shared {
+			return fmt.Errorf("Expected screenboard %d to be shared: %t, got %t", i, shared, board.Shared)
+		}
+		if url := r.Attributes["public_url"]; (url != "") != shared {
+			return fmt.Errorf("Expected screenboard %d to have a public URL: %t, got %q", i, shared, url)
+		}
+		return nil
+	}
+}
+
+func testAccCheckDatadogScreenboardDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*providerMeta).client
+
+	for _, r := range s.RootModule().Resources {
+		if r.Type != "datadog_screenboard" {
+			continue
+		}
+		i, _ := strconv.Atoi(r.Primary.ID)
+		if _, err := client.GetScreenboard(i); err != nil {
+			if datadog.IsNotFound(err) {
+				continue
+			}
+			return fmt.Errorf("Received an error retrieving screenboard %s", err)
+		}
+		return fmt.Errorf("Screenboard still exists")
+	}
+	return nil
+}
+
+const testAccCheckDatadogScreenboardConfig = `
+resource "datadog_screenboard" "foo" {
+  title = "foo screenboard"
+
+  timeseries {
+	x = 1
+	y = 2
+	width = 40
+	height = 15
+	title = true
+	title_text = "CPU"
+	timeframe = "1h"
+	tile_def {
+	  viz = "timeseries"
+	  request {
+		q = "avg:system.cpu.user{$host}"
+		style {
+		  palette = "warm"
+		}
+	  }
+	  event {
+		q = "sources:deploys"
+	  }
+	}
+  }
+
+  query_value {
+	x = 42
+	y = 2
+	width = 20
+	height = 10
+	query = "avg:system.load.1{$host}"
+	precision = 2
+	conditional_format {
+	  comparator = ">"
+	  value = 90
+	  color = "white_on_red"
+	}
+  }
+
+  note {
+	x = 1
+	y = 20
+	width = 30
+	height = 5
+	html = "Owned by the web team"
+	tick = true
+	tick_edge = "top"
+  }
+
+  template_variable {
+	name = "host"
+	prefix = "host"
+  }
+}
+`
+
+const testAccCheckDatadogScreenboardConfigUpdated = `
+resource "datadog_screenboard" "foo" {
+  title = "foo screenboard"
+
+  timeseries {
+	x = 1
+	y = 2
+	width = 60
+	height = 15
+	tile_def {
+	  viz = "timeseries"
+	  request {
+		q = "avg:system.cpu.user{*}"
+	  }
+	}
+  }
+
+  iframe {
+	x = 62
+	y = 2
+	width = 30
+	height = 20
+	url = "https://status.example.com"
+  }
+}
+`
+
+func testAccCheckDatadogScreenboardConfigShared(title string, shared bool) string {
+	return fmt.Sprintf(`
+resource "datadog_screenboard" "tv" {
+  title = "%s"
+  shared = %t
+
+  free_text {
+	x = 1
+	y = 1
+	width = 20
+	height = 5
+	text = "Web"
+  }
+}
+`, title, shared)
+}
+
+// testScreenboardJSON is a board with one widget of each type, with every
+// setting the provider knows about set, and some only the UI sets.
+const testScreenboardJSON = `{
+  "id": 1,
+  "board_title": "Everything",
+  "height": "768",
+  "width": "1024",
+  "templated": true,
+  "template_variables": [{"name": "host", "prefix": "host", "default": "host:web-1"}],
+  "widgets": [
+    {"type": "timeseries", "x": 1, "y": 2, "width": 40, "height": 15,
+     "title": true, "title_text": "CPU", "title_size": 16, "title_align": "left",
+     "timeframe": "1h", "legend": true, "time": {"live_span": "1h"},
+     "tile_def": {"viz": "timeseries", "autoscale": true, "precision": "2", "custom_unit": "%",
+       "yaxis": {"min": "0", "scale": "log"},
+       "requests": [{"q": "avg:system.cpu.user{$host}", "type": "area", "aggregator": "avg",
+         "style": {"palette": "warm", "width": "thin"},
+         "conditional_formats": [{"color": "white_on_red", "comparator": ">", "invert": true, "value": 0.5}]}],
+       "markers": [{"type": "error dashed", "value": "y > 90", "label": "high"}],
+       "events": [{"q": "sources:deploys"}]}},
+    {"type": "query_value", "x": 42, "y": 2, "width": 20, "height": 10,
+     "title": true, "title_text": "Load", "title_size": 16, "title_align": "center",
+     "timeframe": "5m", "aggr": "avg", "aggregator": "last", "calc_func": "raw", "res_calc_func": "raw",
+     "conditional_formats": [{"color": "white_on_green", "comparator": "<", "value": 2}],
+     "is_valid_query": true, "metric": "system.load.1", "metric_type": "standard", "precision": 2,
+     "query": "avg:system.load.1{$host}", "tags": ["$host"], "text_align": "left", "text_size": "auto",
+     "unit": "auto"},
+    {"type": "event_stream", "x": 1, "y": 20, "width": 30, "height": 30,
+     "title": true, "title_text": "Events", "title_size": 14, "title_align": "left",
+     "query": "sources:deploys", "timeframe": "1d", "event_size": "s", "time": {"live_span": "1d"}},
+    {"type": "free_text", "x": 70, "y": 1, "width": 20, "height": 5,
+     "text": "Web", "color": "#4d4d4d", "font_size": "auto", "text_align": "left"},
+    {"type": "toplist", "x": 32, "y": 20, "width": 30, "height": 20,
+     "title": true, "title_text": "Top hosts", "title_size": 16, "title_align": "left",
+     "timeframe": "1h", "legend": true, "legend_size": 4,
+     "tile_def": {"viz": "toplist", "requests": [{"q": "top(avg:system.cpu.user{*} by {host}, 10, 'mean', 'desc')"}]}},
+    {"type": "image", "x": 92, "y": 1, "width": 10, "height": 10,
+     "title": true, "title_text": "Logo", "title_size": 12, "title_align": "right",
+     "url": "https://example.com/logo.png", "sizing": "fit"},
+    {"type": "change", "x": 64, "y": 20, "width": 20, "height": 20,
+     "title": true, "title_text": "Change", "title_size": 16, "title_align": "left",
+     "aggregator": "avg",
+     "tile_def": {"viz": "change", "requests": [{"q": "avg:system.cpu.user{*} by {host}"}]}},
+    {"type": "graph", "x": 86, "y": 20, "width": 20, "height": 20,
+     "title": true, "title_text": "Graph", "title_size": 16, "title_align": "left",
+     "timeframe": "4h", "legend": true, "legend_size": 2,
+     "tile_def": {"viz": "heatmap", "requests": [{"q": "avg:system.cpu.user{*} by {host}"}]}},
+    {"type": "event_timeline", "x": 1, "y": 52, "width": 60, "height": 10,
+     "title": true, "title_text": "Timeline", "title_size": 16, "title_align": "left",
+     "query": "tags:release", "timeframe": "1w"},
+    {"type": "alert_value", "x": 62, "y": 52, "width": 15, "height": 10,
+     "title": true, "title_text": "Errors", "title_size": 16, "title_align": "left",
+     "alert_id": 123, "precision": 1, "text_align": "center", "text_size": "auto", "unit": "%",
+     "timeframe": "1h", "add_timeframe": true},
+    {"type": "alert_graph", "x": 78, "y": 52, "width": 30, "height": 10,
+     "title": true, "title_text": "Errors over time", "title_size": 16, "title_align": "left",
+     "alert_id": 123, "viz_type": "timeseries", "timeframe": "1h", "add_timeframe": true},
+    {"type": "hostmap", "x": 1, "y": 64, "width": 40, "height": 30,
+     "title": true, "title_text": "Hosts", "title_size": 16, "title_align": "left",
+     "query": "role:web", "timeframe": "1h", "legend": true, "legend_size": 1,
+     "tile_def": {"viz": "hostmap", "requests": [{"q": "avg:system.cpu.user{*} by {host}", "type": "fill"}]}},
+    {"type": "check_status", "x": 42, "y": 64, "width": 15, "height": 10,
+     "title": true, "title_text": "NTP", "title_size": 16, "title_align": "left",
+     "check": "ntp.in_sync", "group": "host:web-1", "grouping": "check", "tags": ["role:web"],
+     "timeframe": "10m", "text_align": "center", "text_size": "auto"},
+    {"type": "iframe", "x": 58, "y": 64, "width": 30, "height": 30,
+     "title": true, "title_text": "Status", "title_size": 16, "title_align": "left",
+     "url": "https://status.example.com"},
+    {"type": "note", "x": 90, "y": 64, "width": 20, "height": 10,
+     "title": true, "title_text": "Note", "title_size": 16, "title_align": "left",
+     "html": "Owned by the web team", "bgcolor": "yellow", "font_size": 14, "text_align": "left",
+     "tick": true, "tick_pos": "50%", "tick_edge": "left", "auto_refresh": true, "refresh_every": 30}
+  ]
+}`
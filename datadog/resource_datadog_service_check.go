@@ -1,11 +1,11 @@
 package datadog
 
 import (
-	"bytes"
 	"fmt"
 	"log"
 
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/ojongerius/terraform-provider-datadog/query"
 	"github.com/zorkian/go-datadog-api"
 )
 
@@ -13,11 +13,14 @@ import (
 func resourceDatadogServiceCheck() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceDatadogServiceCheckCreate,
-		Read:   resourceDatadogGenericRead,
+		Read:   resourceDatadogServiceCheckRead,
 		Update: resourceDatadogServiceCheckUpdate,
-		Delete: resourceDatadogGenericDelete,
+		Delete: resourceDatadogLegacyMonitorDelete,
 		Exists: resourceDatadogGenericExists,
 
+		SchemaVersion: monitorSchemaVersion,
+		MigrateState:  migrateMonitorState,
+
 		Schema: map[string]*schema.Schema{
 			"name": &schema.Schema{
 				Type:     schema.TypeString,
@@ -61,77 +64,43 @@ func resourceDatadogServiceCheck() *schema.Resource {
 				Optional: true,
 				Default:  0,
 			},
+
+			// Read only, set when a pre 0.0.4 ID is migrated
+			"legacy_monitor_ids": legacyMonitorIDsSchema(),
 		},
 	}
 }
 
-// buildServiceCheckStruct returns a monitor struct
-func buildServiceCheckStruct(d *schema.ResourceData) *datadog.Monitor {
-	log.Print("[DEBUG] building monitor struct")
-	name := d.Get("name").(string)
-	message := d.Get("message").(string)
-
-	// Tags are are no separate resource/gettable, so some trickery is needed
-	var buffer bytes.Buffer
-	if raw, ok := d.GetOk("tags"); ok {
-		list := raw.([]interface{})
-		length := (len(list) - 1)
-		for i, v := range list {
-			buffer.WriteString(fmt.Sprintf("\"%s\"", v))
-			if i != length {
-				buffer.WriteString(",")
-			}
-
-		}
-	}
+// buildServiceCheckQuery returns the query described by d, such as
+// "http.can_connect".over("instance:buildeng_http","production").last(2).count_by_status()
+func buildServiceCheckQuery(d *schema.ResourceData) *query.ServiceCheck {
+	checkCount, _ := getThresholds(d)
 
-	tagsParsed := buffer.String()
-
-	// Keys are used for multi alerts
-	var b bytes.Buffer
-	if raw, ok := d.GetOk("keys"); ok {
-		list := raw.([]interface{})
-		b.WriteString(".by(")
-		length := (len(list) - 1)
-		for i, v := range list {
-			b.WriteString(fmt.Sprintf("\"%s\"", v))
-			if i != length {
-				b.WriteString(",")
-			}
-
-		}
-		b.WriteString(")")
+	return &query.ServiceCheck{
+		Check: d.Get("check").(string),
+		Scope: getStringList(d, "tags"),
+		By:    getStringList(d, "keys"),
+		Last:  checkCount,
 	}
+}
 
-	keys := b.String()
-
-	var monitorName string
-	var query string
-
-	check := d.Get("check").(string)
-
-	// Examples queries
-	// "http.can_connect".over("instance:buildeng_http","production").last(2).count_by_status()
-	// "http.can_connect".over("*").by("host","instance","url").last(2).count_by_status()
+// buildServiceCheckStruct returns a monitor struct
+func buildServiceCheckStruct(d *schema.ResourceData) *datadog.Monitor {
+	log.Print("[DEBUG] building monitor struct")
 
-	checkCount, thresholds := getThresholds(d)
+	q := buildServiceCheckQuery(d).String()
+	log.Printf("[DEBUG] submitting query: %s", q)
 
-	query = fmt.Sprintf("\"%s\".over(%s)%s.last(%s).count_by_status()", check, tagsParsed, keys, checkCount)
-	log.Print(fmt.Sprintf("[DEBUG] submitting query: %s", query))
-	monitorName = name
+	_, thresholds := getThresholds(d)
 
-	o := datadog.Options{
-		NotifyNoData:     d.Get("notify_no_data").(bool),
-		NoDataTimeframe:  d.Get("no_data_timeframe").(int),
-		RenotifyInterval: d.Get("renotify_interval").(int),
-		Thresholds:       thresholds,
-	}
+	o := buildMonitorOptions(d)
+	o.Thresholds = thresholds
 
 	m := datadog.Monitor{
 		Type:    "service check",
-		Query:   query,
-		Name:    monitorName,
-		Message: message,
+		Query:   q,
+		Name:    d.Get("name").(string),
+		Message: d.Get("message").(string),
 		Options: o,
 	}
 
@@ -146,6 +115,32 @@ func resourceDatadogServiceCheckCreate(d *schema.ResourceData, meta interface{})
 	if err := monitorCreator(d, meta, m); err != nil {
 		return err
 	}
+	readLegacyMonitorIDs(d)
+
+	return nil
+}
+
+// resourceDatadogServiceCheckRead syncs the state with the monitor in Datadog.
+func resourceDatadogServiceCheckRead(d *schema.ResourceData, meta interface{}) error {
+	m, err := readMonitor(d, meta)
+	if err != nil || m == nil {
+		return err
+	}
+
+	q, err := query.ParseServiceCheck(m.Query)
+	if err != nil {
+		return fmt.Errorf("monitor %d has a query that cannot be expressed with check, tags and keys, "+
+			"manage it with datadog_monitor instead: %s", m.Id, err)
+	}
+	if !query.Equal(q, buildServiceCheckQuery(d)) {
+		d.Set("check", q.Check)
+		readScope(d, q.Scope)
+		d.Set("keys", q.By)
+	}
+
+	readMonitorOptions(d, resourceDatadogServiceCheck().Schema, m)
+	readLegacyMonitorIDs(d)
+	readThresholds(d, m.Options.Thresholds)
 
 	return nil
 }
@@ -155,7 +150,7 @@ func resourceDatadogServiceCheckUpdate(d *schema.ResourceData, meta interface{})
 	log.Printf("[DEBUG] running update.")
 
 	m := buildServiceCheckStruct(d)
-	if err := monitorUpdater(d, meta, m); err != nil {
+	if err := monitorUpdater(d, meta, m, nil); err != nil {
 		return err
 	}
 
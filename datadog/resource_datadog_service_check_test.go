@@ -5,7 +5,6 @@ import (
 
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/terraform"
-	"github.com/zorkian/go-datadog-api"
 )
 
 func TestAccDatadogServiceCheck_Basic(t *testing.T) {
@@ -54,7 +53,7 @@ func TestAccDatadogServiceCheck_Basic(t *testing.T) {
 }
 
 func testAccCheckDatadogServiceCheckDestroy(s *terraform.State) error {
-	client := testAccProvider.Meta().(*datadog.Client)
+	client := testAccProvider.Meta().(*providerMeta).client
 
 	if err := destroyHelper(s, client); err != nil {
 		return err
@@ -64,7 +63,7 @@ func testAccCheckDatadogServiceCheckDestroy(s *terraform.State) error {
 
 func testAccCheckDatadogServiceCheckExists(n string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*datadog.Client)
+		client := testAccProvider.Meta().(*providerMeta).client
 		if err := existsHelper(s, client); err != nil {
 			return err
 		}
@@ -0,0 +1,347 @@
+package datadog
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/zorkian/go-datadog-api"
+)
+
+// resourceDatadogTimeboard is a Datadog timeboard resource, a dashboard of
+// graphs that share a time frame.
+func resourceDatadogTimeboard() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceDatadogTimeboardCreate,
+		Read:   resourceDatadogTimeboardRead,
+		Update: resourceDatadogTimeboardUpdate,
+		Delete: resourceDatadogTimeboardDelete,
+
+		Schema: map[string]*schema.Schema{
+			"title": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"description": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			// Graphs are a list, so graphs added or reordered in Datadog
+			// show up as a change.
+			"graph": &schema.Schema{
+				Type:     schema.TypeList,
+				Required: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"title": &schema.Schema{
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"viz": &schema.Schema{
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"request": &schema.Schema{
+							Type:     schema.TypeList,
+							Required: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"q": &schema.Schema{
+										Type:     schema.TypeString,
+										Required: true,
+									},
+									"stacked": &schema.Schema{
+										Type:     schema.TypeBool,
+										Optional: true,
+									},
+								},
+							},
+						},
+						"events": &schema.Schema{
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"marker": &schema.Schema{
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"type": &schema.Schema{
+										Type:     schema.TypeString,
+										Required: true,
+									},
+									"value": &schema.Schema{
+										Type:     schema.TypeString,
+										Required: true,
+									},
+									"label": &schema.Schema{
+										Type:     schema.TypeString,
+										Optional: true,
+									},
+								},
+							},
+						},
+						// A single block, as helper/schema does not
+						// check the attributes of a map.
+						"yaxis": &schema.Schema{
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"min": &schema.Schema{
+										Type:         schema.TypeString,
+										Optional:     true,
+										ValidateFunc: validateYaxisBound,
+									},
+									"max": &schema.Schema{
+										Type:         schema.TypeString,
+										Optional:     true,
+										ValidateFunc: validateYaxisBound,
+									},
+									"scale": &schema.Schema{
+										Type:         schema.TypeString,
+										Optional:     true,
+										ValidateFunc: validateYaxisScale,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"template_variable": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": &schema.Schema{
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"prefix": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"default": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func validateYaxisBound(v interface{}, k string) (ws []string, es []error) {
+	if _, err := strconv.ParseFloat(v.(string), 64); err != nil && v.(string) != "auto" {
+		es = append(es, fmt.Errorf("%q must be a number or \"auto\", got %q", k, v))
+	}
+	return
+}
+
+// yaxisScales are the scales a graph's y-axis can have.
+var yaxisScales = map[string]bool{"linear": true, "log": true, "pow": true, "sqrt": true}
+
+func validateYaxisScale(v interface{}, k string) (ws []string, es []error) {
+	if !yaxisScales[v.(string)] {
+		es = append(es, fmt.Errorf("%q must be one of linear, log, pow or sqrt, got %q", k, v))
+	}
+	return
+}
+
+// buildTimeboardStruct returns the dashboard of d.
+func buildTimeboardStruct(d *schema.ResourceData) (*datadog.Dashboard, error) {
+	dash := datadog.Dashboard{
+		Title:             d.Get("title").(string),
+		Description:       d.Get("description").(string),
+		Graphs:            []datadog.Graph{},
+		TemplateVariables: []datadog.TemplateVariable{},
+	}
+
+	for i := 0; i < d.Get("graph.#").(int); i++ {
+		g, err := buildGraph(d, fmt.Sprintf("graph.%d.", i))
+		if err != nil {
+			return nil, fmt.Errorf("graph %d: %s", i, err)
+		}
+		dash.Graphs = append(dash.Graphs, g)
+	}
+
+	for i := 0; i < d.Get("template_variable.#").(int); i++ {
+		prefix := fmt.Sprintf("template_variable.%d.", i)
+		dash.TemplateVariables = append(dash.TemplateVariables, datadog.TemplateVariable{
+			Name:    d.Get(prefix + "name").(string),
+			Prefix:  d.Get(prefix + "prefix").(string),
+			Default: d.Get(prefix + "default").(string),
+		})
+	}
+
+	return &dash, nil
+}
+
+// buildGraph returns the graph whose attributes start with prefix.
+func buildGraph(d *schema.ResourceData, prefix string) (datadog.Graph, error) {
+	g := datadog.Graph{Title: d.Get(prefix + "title").(string)}
+	g.Definition.Viz = d.Get(prefix + "viz").(string)
+	g.Definition.Requests = []datadog.GraphDefinitionRequest{}
+
+	for i := 0; i < d.Get(prefix+"request.#").(int); i++ {
+		r := fmt.Sprintf("%srequest.%d.", prefix, i)
+		g.Definition.Requests = append(g.Definition.Requests, datadog.GraphDefinitionRequest{
+			Query:   d.Get(r + "q").(string),
+			Stacked: d.Get(r + "stacked").(bool),
+		})
+	}
+
+	for _, q := range getStringList(d, prefix+"events") {
+		g.Definition.Events = append(g.Definition.Events, datadog.GraphEvent{Query: q})
+	}
+
+	for i := 0; i < d.Get(prefix+"marker.#").(int); i++ {
+		m := fmt.Sprintf("%smarker.%d.", prefix, i)
+		g.Definition.Markers = append(g.Definition.Markers, datadog.GraphDefinitionMarker{
+			Type:  d.Get(m + "type").(string),
+			Value: d.Get(m + "value").(string),
+			Label: d.Get(m + "label").(string),
+		})
+	}
+
+	switch n := d.Get(prefix + "yaxis.#").(int); {
+	case n > 1:
+		return g, fmt.Errorf("a graph takes one yaxis block, got %d", n)
+	case n == 1:
+		g.Definition.Yaxis = &datadog.Yaxis{
+			Min:   datadog.YaxisBound(d.Get(prefix + "yaxis.0.min").(string)),
+			Max:   datadog.YaxisBound(d.Get(prefix + "yaxis.0.max").(string)),
+			Scale: d.Get(prefix + "yaxis.0.scale").(string),
+		}
+	}
+
+	return g, nil
+}
+
+// readGraphs returns graphs in the form of the graph attribute.
+func readGraphs(graphs []datadog.Graph) []map[string]interface{} {
+	list := []map[string]interface{}{}
+	for _, g := range graphs {
+		requests := []map[string]interface{}{}
+		for _, r := range g.Definition.Requests {
+			requests = append(requests, map[string]interface{}{"q": r.Query, "stacked": r.Stacked})
+		}
+
+		events := []string{}
+		for _, e := range g.Definition.Events {
+			events = append(events, e.Query)
+		}
+
+		markers := []map[string]interface{}{}
+		for _, m := range g.Definition.Markers {
+			markers = append(markers, map[string]interface{}{"type": m.Type, "value": m.Value, "label": m.Label})
+		}
+
+		yaxis := []map[string]interface{}{}
+		if y := g.Definition.Yaxis; y != nil && *y != (datadog.Yaxis{}) {
+			yaxis = append(yaxis, map[string]interface{}{
+				"min": string(y.Min), "max": string(y.Max), "scale": y.Scale})
+		}
+
+		list = append(list, map[string]interface{}{
+			"title":   g.Title,
+			"viz":     g.Definition.Viz,
+			"request": requests,
+			"events":  events,
+			"marker":  markers,
+			"yaxis":   yaxis,
+		})
+	}
+	return list
+}
+
+func resourceDatadogTimeboardCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*providerMeta).client
+
+	dash, err := buildTimeboardStruct(d)
+	if err != nil {
+		return err
+	}
+
+	dash, err = client.CreateDashboard(dash)
+	if err != nil {
+		return fmt.Errorf("error creating timeboard: %s", err.Error())
+	}
+	d.SetId(strconv.Itoa(dash.Id))
+
+	return resourceDatadogTimeboardRead(d, meta)
+}
+
+func resourceDatadogTimeboardRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*providerMeta).client
+
+	i, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return err
+	}
+
+	dash, err := client.GetDashboard(i)
+	if datadog.IsNotFound(err) {
+		log.Printf("[WARN] timeboard %d not found, removing from state", i)
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error reading timeboard %d: %s", i, err)
+	}
+
+	d.Set("title", dash.Title)
+	d.Set("description", dash.Description)
+	d.Set("graph", readGraphs(dash.Graphs))
+
+	variables := []map[string]interface{}{}
+	for _, v := range dash.TemplateVariables {
+		variables = append(variables, map[string]interface{}{
+			"name": v.Name, "prefix": v.Prefix, "default": v.Default})
+	}
+	d.Set("template_variable", variables)
+
+	return nil
+}
+
+// resourceDatadogTimeboardUpdate replaces the timeboard with the one
+// configured, including its graphs.
+func resourceDatadogTimeboardUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*providerMeta).client
+
+	i, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return err
+	}
+
+	dash, err := buildTimeboardStruct(d)
+	if err != nil {
+		return err
+	}
+	dash.Id = i
+	if err := client.UpdateDashboard(dash); err != nil {
+		return fmt.Errorf("error updating timeboard %d: %s", i, err.Error())
+	}
+
+	return resourceDatadogTimeboardRead(d, meta)
+}
+
+func resourceDatadogTimeboardDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*providerMeta).client
+
+	i, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return err
+	}
+
+	// A timeboard that is already gone needs no deleting.
+	if err = client.DeleteDashboard(i); err != nil && !datadog.IsNotFound(err) {
+		return fmt.Errorf("error deleting timeboard %d: %s", i, err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,272 @@
+package datadog
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/zorkian/go-datadog-api"
+)
+
+func TestAccDatadogTimeboard_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDatadogTimeboardDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckDatadogTimeboardConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDatadogTimeboardGraphs("datadog_timeboard.foo", "CPU", "Load"),
+					resource.TestCheckResourceAttr(
+						"datadog_timeboard.foo", "title", "foo timeboard"),
+					resource.TestCheckResourceAttr(
+						"datadog_timeboard.foo", "graph.#", "2"),
+					resource.TestCheckResourceAttr(
+						"datadog_timeboard.foo", "graph.0.viz", "timeseries"),
+					resource.TestCheckResourceAttr(
+						"datadog_timeboard.foo", "graph.0.request.#", "2"),
+					resource.TestCheckResourceAttr(
+						"datadog_timeboard.foo", "graph.0.request.1.q", "avg:system.cpu.system{$host}"),
+					resource.TestCheckResourceAttr(
+						"datadog_timeboard.foo", "graph.0.request.1.stacked", "true"),
+					resource.TestCheckResourceAttr(
+						"datadog_timeboard.foo", "graph.0.events.0", "sources:deploys"),
+					resource.TestCheckResourceAttr(
+						"datadog_timeboard.foo", "graph.0.marker.0.type", "error dashed"),
+					resource.TestCheckResourceAttr(
+						"datadog_timeboard.foo", "graph.0.marker.0.value", "y > 90"),
+					resource.TestCheckResourceAttr(
+						"datadog_timeboard.foo", "graph.0.yaxis.0.min", "0"),
+					resource.TestCheckResourceAttr(
+						"datadog_timeboard.foo", "graph.0.yaxis.0.max", "auto"),
+					resource.TestCheckResourceAttr(
+						"datadog_timeboard.foo", "graph.1.request.0.stacked", "false"),
+					resource.TestCheckResourceAttr(
+						"datadog_timeboard.foo", "template_variable.0.name", "host"),
+					resource.TestCheckResourceAttr(
+						"datadog_timeboard.foo", "template_variable.0.prefix", "host"),
+					testAccCheckDatadogTimeboardYaxis("datadog_timeboard.foo"),
+				),
+			},
+			resource.TestStep{
+				Config: testAccCheckDatadogTimeboardConfigUpdated,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDatadogTimeboardGraphs("datadog_timeboard.foo", "Load", "CPU user"),
+					resource.TestCheckResourceAttr(
+						"datadog_timeboard.foo", "graph.1.marker.#", "0"),
+					resource.TestCheckResourceAttr(
+						"datadog_timeboard.foo", "template_variable.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDatadogTimeboard_ChangedOutsideTerraform(t *testing.T) {
+	if testAccFake == nil {
+		t.Skip("Changing a timeboard outside Terraform needs the fake API, TF_ACC is set")
+	}
+
+	var id int
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDatadogTimeboardDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckDatadogTimeboardConfig,
+				Check: func(s *terraform.State) error {
+					id, _ = strconv.Atoi(s.RootModule().Resources["datadog_timeboard.foo"].Primary.ID)
+					return nil
+				},
+			},
+			resource.TestStep{
+				// Graphs reordered and added in the UI are put back.
+				PreConfig: func() {
+					graphs := testAccFake.Dashboard(id)["graphs"].([]interface{})
+					graphs = []interface{}{graphs[1], graphs[0], map[string]interface{}{
+						"title": "Added", "definition": map[string]interface{}{"viz": "timeseries"}}}
+					testAccFake.UpdateDashboard(id, map[string]interface{}{"graphs": graphs})
+				},
+				Config: testAccCheckDatadogTimeboardConfig,
+				Check:  testAccCheckDatadogTimeboardGraphs("datadog_timeboard.foo", "CPU", "Load"),
+			},
+		},
+	})
+}
+
+// testAccCheckDatadogTimeboardGraphs checks timeboard n has graphs with the
+// given titles, in order.
+func TestResourceDatadogTimeboard_Validate(t *testing.T) {
+	cases := []struct {
+		Name   string
+		Yaxis  map[string]interface{}
+		Errors int
+	}{
+		{"valid", map[string]interface{}{"min": "0", "max": "auto", "scale": "log"}, 0},
+		{"misspelt attribute", map[string]interface{}{"scal": "log"}, 1},
+		{"bound that is not a number", map[string]interface{}{"min": "low"}, 1},
+		{"unknown scale", map[string]interface{}{"scale": "cubic"}, 1},
+	}
+
+	for _, tc := range cases {
+		raw, err := config.NewRawConfig(map[string]interface{}{
+			"title":       "foo",
+			"description": "bar",
+			"graph": []map[string]interface{}{{
+				"title":   "CPU",
+				"viz":     "timeseries",
+				"request": []map[string]interface{}{{"q": "avg:system.cpu.user{*}"}},
+				"yaxis":   []map[string]interface{}{tc.Yaxis},
+			}},
+		})
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		_, errs := resourceDatadogTimeboard().Validate(terraform.NewResourceConfig(raw))
+		if len(errs) != tc.Errors {
+			t.Fatalf("%s: expected %d errors, got %v", tc.Name, tc.Errors, errs)
+		}
+	}
+
+	d := resourceDatadogTimeboard().TestResourceData()
+	d.Set("title", "two yaxis blocks")
+	d.Set("graph", []map[string]interface{}{{
+		"title": "CPU",
+		"viz":   "timeseries",
+		"yaxis": []map[string]interface{}{{"min": "0"}, {"max": "1"}},
+	}})
+	if _, err := buildTimeboardStruct(d); err == nil {
+		t.Fatalf("Expected an error for a graph with two yaxis blocks")
+	}
+}
+
+func testAccCheckDatadogTimeboardGraphs(n string, titles ...string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*providerMeta).client
+		i, _ := strconv.Atoi(s.RootModule().Resources[n].Primary.ID)
+		dash, err := client.GetDashboard(i)
+		if err != nil {
+			return fmt.Errorf("Received an error retrieving timeboard %s", err)
+		}
+
+		var got []string
+		for _, g := range dash.Graphs {
+			got = append(got, g.Title)
+		}
+		if !reflect.DeepEqual(got, titles) {
+			return fmt.Errorf("Expected timeboard %d to have graphs %v, got %v", i, titles, got)
+		}
+		return nil
+	}
+}
+
+// testAccCheckDatadogTimeboardYaxis checks numeric y-axis bounds are sent as
+// numbers and "auto" as a string.
+func testAccCheckDatadogTimeboardYaxis(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if testAccFake == nil {
+			return nil
+		}
+		i, _ := strconv.Atoi(s.RootModule().Resources[n].Primary.ID)
+		graph := testAccFake.Dashboard(i)["graphs"].([]interface{})[0].(map[string]interface{})
+		yaxis := graph["definition"].(map[string]interface{})["yaxis"].(map[string]interface{})
+		if min, ok := yaxis["min"].(float64); !ok || min != 0 {
+			return fmt.Errorf("Expected yaxis min to be sent as the number 0, got %#v", yaxis["min"])
+		}
+		if max, ok := yaxis["max"].(string); !ok || max != "auto" {
+			return fmt.Errorf("Expected yaxis max to be sent as \"auto\", got %#v", yaxis["max"])
+		}
+		return nil
+	}
+}
+
+func testAccCheckDatadogTimeboardDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*providerMeta).client
+
+	for _, r := range s.RootModule().Resources {
+		if r.Type != "datadog_timeboard" {
+			continue
+		}
+		i, _ := strconv.Atoi(r.Primary.ID)
+		if _, err := client.GetDashboard(i); err != nil {
+			if datadog.IsNotFound(err) {
+				continue
+			}
+			return fmt.Errorf("Received an error retrieving timeboard %s", err)
+		}
+		return fmt.Errorf("Timeboard still exists")
+	}
+	return nil
+}
+
+const testAccCheckDatadogTimeboardConfig = `
+resource "datadog_timeboard" "foo" {
+  title = "foo timeboard"
+  description = "created using the Datadog provider in Terraform"
+
+  graph {
+	title = "CPU"
+	viz = "timeseries"
+	request {
+	  q = "avg:system.cpu.user{$host}"
+	}
+	request {
+	  q = "avg:system.cpu.system{$host}"
+	  stacked = true
+	}
+	events = ["sources:deploys"]
+	marker {
+	  type = "error dashed"
+	  value = "y > 90"
+	  label = "high"
+	}
+	yaxis {
+	  min = "0"
+	  max = "auto"
+	}
+  }
+
+  graph {
+	title = "Load"
+	viz = "timeseries"
+	request {
+	  q = "avg:system.load.1{$host} by {host}"
+	}
+  }
+
+  template_variable {
+	name = "host"
+	prefix = "host"
+  }
+}
+`
+
+const testAccCheckDatadogTimeboardConfigUpdated = `
+resource "datadog_timeboard" "foo" {
+  title = "foo timeboard"
+  description = "created using the Datadog provider in Terraform"
+
+  graph {
+	title = "Load"
+	viz = "timeseries"
+	request {
+	  q = "avg:system.load.1{*} by {host}"
+	}
+  }
+
+  graph {
+	title = "CPU user"
+	viz = "query_value"
+	request {
+	  q = "avg:system.cpu.user{*}"
+	}
+  }
+}
+`
@@ -0,0 +1,150 @@
+package datadog
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/zorkian/go-datadog-api"
+)
+
+// resourceDatadogUser is a member of the Datadog organization. Its ID is the
+// user's handle, the email address they were invited with.
+func resourceDatadogUser() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceDatadogUserCreate,
+		Read:   resourceDatadogUserRead,
+		Update: resourceDatadogUserUpdate,
+		Delete: resourceDatadogUserDelete,
+
+		Schema: map[string]*schema.Schema{
+			"handle": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"email": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			// Users can set their own name once they accept the invite.
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			// Datadog can give users a role of its own when none is set.
+			"role": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"is_admin": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"disabled": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			// Datadog keeps disabled users, and their history. Without
+			// disable_on_destroy a destroyed user is left as it is.
+			"disable_on_destroy": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+
+			// Read only, false while the invite is pending
+			"verified": &schema.Schema{
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// buildUserStruct returns the user of d.
+func buildUserStruct(d *schema.ResourceData) datadog.User {
+	return datadog.User{
+		Handle:   d.Get("handle").(string),
+		Email:    d.Get("email").(string),
+		Name:     d.Get("name").(string),
+		Role:     d.Get("role").(string),
+		IsAdmin:  d.Get("is_admin").(bool),
+		Disabled: d.Get("disabled").(bool),
+	}
+}
+
+// resourceDatadogUserCreate invites the user, then sets what the invite does
+// not. Inviting a user that exists, such as one disabled earlier, adopts it.
+func resourceDatadogUserCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*providerMeta).client
+
+	handle := d.Get("handle").(string)
+	if err := client.InviteUsers([]string{handle}); err != nil {
+		return fmt.Errorf("error inviting user %s: %s", handle, err.Error())
+	}
+	d.SetId(handle)
+
+	if err := client.UpdateUser(buildUserStruct(d)); err != nil {
+		return fmt.Errorf("error updating user %s: %s", handle, err.Error())
+	}
+
+	return resourceDatadogUserRead(d, meta)
+}
+
+func resourceDatadogUserRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*providerMeta).client
+
+	u, err := client.GetUser(d.Id())
+	if datadog.IsNotFound(err) {
+		log.Printf("[WARN] user %s not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error reading user %s: %s", d.Id(), err)
+	}
+
+	d.Set("handle", u.Handle)
+	d.Set("email", u.Email)
+	d.Set("name", u.Name)
+	d.Set("role", u.Role)
+	d.Set("is_admin", u.IsAdmin)
+	d.Set("disabled", u.Disabled)
+	d.Set("verified", u.Verified)
+
+	return nil
+}
+
+func resourceDatadogUserUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*providerMeta).client
+
+	if err := client.UpdateUser(buildUserStruct(d)); err != nil {
+		return fmt.Errorf("error updating user %s: %s", d.Id(), err.Error())
+	}
+
+	return resourceDatadogUserRead(d, meta)
+}
+
+// resourceDatadogUserDelete disables the user, which is what a delete does in
+// Datadog, unless disable_on_destroy is turned off.
+func resourceDatadogUserDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*providerMeta).client
+
+	if !d.Get("disable_on_destroy").(bool) {
+		log.Printf("[INFO] leaving user %s as it is, disable_on_destroy is false", d.Id())
+		return nil
+	}
+
+	// A user that is already gone needs no disabling.
+	if err := client.DeleteUser(d.Id()); err != nil && !datadog.IsNotFound(err) {
+		return fmt.Errorf("error disabling user %s: %s", d.Id(), err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,269 @@
+package datadog
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/zorkian/go-datadog-api"
+)
+
+func TestAccDatadogUser_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDatadogUserDestroy(true),
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckDatadogUserConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDatadogUserExists("datadog_user.foo"),
+					resource.TestCheckResourceAttr(
+						"datadog_user.foo", "handle", "terraform-user@example.com"),
+					resource.TestCheckResourceAttr(
+						"datadog_user.foo", "email", "terraform-user@example.com"),
+					resource.TestCheckResourceAttr(
+						"datadog_user.foo", "name", "Terraform User"),
+					resource.TestCheckResourceAttr(
+						"datadog_user.foo", "role", "SRE"),
+					resource.TestCheckResourceAttr(
+						"datadog_user.foo", "is_admin", "true"),
+					resource.TestCheckResourceAttr(
+						"datadog_user.foo", "disabled", "false"),
+					resource.TestCheckResourceAttr(
+						"datadog_user.foo", "verified", "false"),
+				),
+			},
+			resource.TestStep{
+				// Accepting the invite verifies the user.
+				PreConfig: func() {
+					if testAccFake != nil {
+						testAccFake.UpdateUser("terraform-user@example.com", map[string]interface{}{"verified": true})
+					}
+				},
+				Config: testAccCheckDatadogUserConfigUpdated,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"datadog_user.foo", "name", "Terraform User, renamed"),
+					resource.TestCheckResourceAttr(
+						"datadog_user.foo", "is_admin", "false"),
+					func(s *terraform.State) error {
+						if testAccFake == nil {
+							return nil
+						}
+						return resource.TestCheckResourceAttr("datadog_user.foo", "verified", "true")(s)
+					},
+				),
+			},
+			resource.TestStep{
+				// A user disabled in the UI is enabled again.
+				PreConfig: func() {
+					if testAccFake != nil {
+						testAccFake.UpdateUser("terraform-user@example.com", map[string]interface{}{"disabled": true})
+					}
+				},
+				Config: testAccCheckDatadogUserConfigUpdated,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDatadogUserExists("datadog_user.foo"),
+					resource.TestCheckResourceAttr(
+						"datadog_user.foo", "disabled", "false"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDatadogUser_KeptOnDestroy(t *testing.T) {
+	if testAccFake == nil {
+		t.Skip("Leaving a user enabled after the test needs the fake API, TF_ACC is set")
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDatadogUserDestroy(false),
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckDatadogUserConfigKept,
+				Check:  testAccCheckDatadogUserExists("datadog_user.kept"),
+			},
+		},
+	})
+}
+
+func TestAccDatadogUser_DefaultRole(t *testing.T) {
+	if testAccFake == nil {
+		t.Skip("Giving a user a role outside Terraform needs the fake API, TF_ACC is set")
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDatadogUserDestroy(true),
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckDatadogUserConfigNoRole,
+				Check: resource.TestCheckResourceAttr(
+					"datadog_user.norole", "role", ""),
+			},
+			resource.TestStep{
+				// A role Datadog gives the user is not a change.
+				PreConfig: func() {
+					testAccFake.UpdateUser("terraform-norole@example.com", map[string]interface{}{"role": "Standard"})
+				},
+				Config: testAccCheckDatadogUserConfigNoRole,
+				Check: resource.TestCheckResourceAttr(
+					"datadog_user.norole", "role", "Standard"),
+			},
+		},
+	})
+}
+
+func TestUserCreateReadDelete(t *testing.T) {
+	if testAccFake == nil {
+		t.Skip("Reading back disabled users needs the fake API, TF_ACC is set")
+	}
+	meta := testAccFake.Meta(t)
+	handle := "terraform-lifecycle@example.com"
+
+	d := resourceDatadogUser().TestResourceData()
+	d.Set("handle", handle)
+	d.Set("name", "Terraform User")
+	d.Set("role", "SRE")
+	d.Set("is_admin", true)
+	d.Set("disable_on_destroy", true)
+	if err := resourceDatadogUserCreate(d, meta); err != nil {
+		t.Fatal(err)
+	}
+	if d.Id() != handle {
+		t.Fatalf("Expected ID %s, got %s", handle, d.Id())
+	}
+	u := testAccFake.User(handle)
+	if u["name"] != "Terraform User" || u["role"] != "SRE" || u["is_admin"] != true || u["disabled"] != false {
+		t.Fatalf("Expected the configured user, got %v", u)
+	}
+
+	// The user renamed themselves after accepting the invite.
+	testAccFake.UpdateUser(handle, map[string]interface{}{"name": "Jane", "verified": true})
+	if err := resourceDatadogUserRead(d, meta); err != nil {
+		t.Fatal(err)
+	}
+	if d.Get("name") != "Jane" || !d.Get("verified").(bool) {
+		t.Fatalf("Expected the renamed, verified user, got name %s, verified %t", d.Get("name"), d.Get("verified"))
+	}
+
+	if err := resourceDatadogUserDelete(d, meta); err != nil {
+		t.Fatal(err)
+	}
+	if u := testAccFake.User(handle); u["disabled"] != true {
+		t.Fatalf("Expected user %s to be disabled, got %v", handle, u)
+	}
+
+	// Creating the disabled user again adopts and enables it.
+	d = resourceDatadogUser().TestResourceData()
+	d.Set("handle", handle)
+	d.Set("name", "Terraform User, again")
+	if err := resourceDatadogUserCreate(d, meta); err != nil {
+		t.Fatal(err)
+	}
+	u = testAccFake.User(handle)
+	if u["disabled"] != false || u["name"] != "Terraform User, again" {
+		t.Fatalf("Expected user %s to be enabled and renamed, got %v", handle, u)
+	}
+	if d.Get("disabled").(bool) || !d.Get("verified").(bool) {
+		t.Fatalf("Expected the adopted user to be enabled and still verified")
+	}
+}
+
+func TestUserDelete_DisableOnDestroyOff(t *testing.T) {
+	if testAccFake == nil {
+		t.Skip("Leaving a user enabled needs the fake API, TF_ACC is set")
+	}
+	meta := testAccFake.Meta(t)
+	handle := "terraform-left@example.com"
+
+	d := resourceDatadogUser().TestResourceData()
+	d.Set("handle", handle)
+	d.Set("disable_on_destroy", false)
+	if err := resourceDatadogUserCreate(d, meta); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := resourceDatadogUserDelete(d, meta); err != nil {
+		t.Fatal(err)
+	}
+	if u := testAccFake.User(handle); u["disabled"] != false {
+		t.Fatalf("Expected user %s to be left enabled, got %v", handle, u)
+	}
+}
+
+// testAccCheckDatadogUserExists checks user n exists and is enabled.
+func testAccCheckDatadogUserExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*providerMeta).client
+		u, err := client.GetUser(s.RootModule().Resources[n].Primary.ID)
+		if err != nil {
+			return fmt.Errorf("Received an error retrieving user %s", err)
+		}
+		if u.Disabled {
+			return fmt.Errorf("Expected user %s to be enabled", u.Handle)
+		}
+		return nil
+	}
+}
+
+// testAccCheckDatadogUserDestroy checks destroyed users were disabled, as
+// Datadog keeps them, or were left enabled when disabled is false.
+func testAccCheckDatadogUserDestroy(disabled bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*providerMeta).client
+
+		for _, r := range s.RootModule().Resources {
+			if r.Type != "datadog_user" {
+				continue
+			}
+			u, err := client.GetUser(r.Primary.ID)
+			if datadog.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("Received an error retrieving user %s", err)
+			}
+			if u.Disabled != disabled {
+				return fmt.Errorf("Expected user %s to be disabled: %t, got %t", r.Primary.ID, disabled, u.Disabled)
+			}
+		}
+		return nil
+	}
+}
+
+const testAccCheckDatadogUserConfig = `
+resource "datadog_user" "foo" {
+  handle = "terraform-user@example.com"
+  name = "Terraform User"
+  role = "SRE"
+  is_admin = true
+}
+`
+
+const testAccCheckDatadogUserConfigUpdated = `
+resource "datadog_user" "foo" {
+  handle = "terraform-user@example.com"
+  name = "Terraform User, renamed"
+  role = "SRE"
+}
+`
+
+const testAccCheckDatadogUserConfigKept = `
+resource "datadog_user" "kept" {
+  handle = "terraform-kept@example.com"
+  disable_on_destroy = false
+}
+`
+
+const testAccCheckDatadogUserConfigNoRole = `
+resource "datadog_user" "norole" {
+  handle = "terraform-norole@example.com"
+}
+`
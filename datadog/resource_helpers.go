@@ -1,12 +1,19 @@
 package datadog
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"github.com/hashicorp/terraform/config"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/ojongerius/terraform-provider-datadog/query"
 	"github.com/zorkian/go-datadog-api"
+	"log"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 )
 
 func thresholdSchema() *schema.Schema {
@@ -29,56 +36,236 @@ func thresholdSchema() *schema.Schema {
 				},
 			},
 		},
+		ValidateFunc: validateThresholds,
 	}
 }
 
+// validateThresholds checks a thresholds block only sets ok, warning and
+// critical, sets them to numbers, and sets critical. helper/schema does not
+// enforce Required in the block, so this is what requires critical.
+func validateThresholds(v interface{}, k string) (ws []string, es []error) {
+	if _, ok := v.(map[string]interface{})["critical"]; !ok {
+		es = append(es, fmt.Errorf("%q needs a critical threshold", k))
+	}
+	for name, value := range v.(map[string]interface{}) {
+		if name != "ok" && name != "warning" && name != "critical" {
+			es = append(es, fmt.Errorf("%q takes ok, warning and critical, got %q", k, name))
+			continue
+		}
+		s := fmt.Sprint(value)
+		if s == config.UnknownVariableValue {
+			continue
+		}
+		if _, err := strconv.ParseFloat(s, 64); err != nil {
+			es = append(es, fmt.Errorf("%q: %s must be a number, got %q", k, name, s))
+		}
+	}
+	return
+}
+
+// validateNumber checks a string attribute holds a number.
+func validateNumber(v interface{}, k string) (ws []string, es []error) {
+	if _, err := strconv.ParseFloat(v.(string), 64); err != nil {
+		es = append(es, fmt.Errorf("%q must be a number, got %q", k, v))
+	}
+	return
+}
+
+// validateOperator checks v is a comparison a monitor query can end in.
+func validateOperator(v interface{}, k string) (ws []string, es []error) {
+	switch v.(string) {
+	case "<", "<=", ">", ">=", "==", "!=":
+	default:
+		es = append(es, fmt.Errorf("%q must be one of <, <=, >, >=, == or !=, got %q", k, v))
+	}
+	return
+}
+
+// checkThresholdOrder checks the thresholds are ordered the way operator
+// compares them. With > a monitor warns before it turns critical, so warning
+// must be below critical and ok below both. With < it is the other way around.
+func checkThresholdOrder(operator string, t datadog.ThresholdCount) error {
+	var below bool
+	switch operator {
+	case ">", ">=":
+		below = true
+	case "<", "<=":
+		below = false
+	default:
+		return nil
+	}
+
+	thresholds := []struct {
+		name  string
+		value json.Number
+	}{{"ok", t.Ok}, {"warning", t.Warning}, {"critical", t.Critical}}
+
+	var prevName string
+	var prevValue json.Number
+	for _, th := range thresholds {
+		if th.value == "" {
+			continue
+		}
+		if prevValue != "" {
+			prev, err := prevValue.Float64()
+			if err != nil {
+				return fmt.Errorf("%s threshold must be a number, got %q", prevName, prevValue)
+			}
+			f, err := th.value.Float64()
+			if err != nil {
+				return fmt.Errorf("%s threshold must be a number, got %q", th.name, th.value)
+			}
+			if below && prev >= f {
+				return fmt.Errorf("with %s the %s threshold (%s) must be below the %s threshold (%s)",
+					operator, prevName, prevValue, th.name, th.value)
+			}
+			if !below && prev <= f {
+				return fmt.Errorf("with %s the %s threshold (%s) must be above the %s threshold (%s)",
+					operator, prevName, prevValue, th.name, th.value)
+			}
+		}
+		prevName, prevValue = th.name, th.value
+	}
+
+	return nil
+}
+
+// getThresholds returns the critical threshold and all thresholds of d, in
+// canonical form.
 func getThresholds(d *schema.ResourceData) (string, datadog.ThresholdCount) {
 	t := datadog.ThresholdCount{}
 
 	var threshold string
 
 	if r, ok := d.GetOk("thresholds.ok"); ok {
-		t.Ok = json.Number(r.(string))
+		t.Ok = json.Number(normalizeNumber(r.(string)))
 	}
 
 	if r, ok := d.GetOk("thresholds.warning"); ok {
-		t.Warning = json.Number(r.(string))
+		t.Warning = json.Number(normalizeNumber(r.(string)))
 	}
 
 	if r, ok := d.GetOk("thresholds.critical"); ok {
-		threshold = r.(string)
-		t.Critical = json.Number(r.(string))
+		threshold = normalizeNumber(r.(string))
+		t.Critical = json.Number(threshold)
 	}
 
 	return threshold, t
 }
 
+// readThresholds sets the thresholds of d from t. A threshold that is equal to
+// the one in state is left as it is, so 3 in the configuration and 3.0 from
+// Datadog is not a change. Other thresholds are stored in canonical form.
+func readThresholds(d *schema.ResourceData, t datadog.ThresholdCount) {
+	thresholds := make(map[string]string)
+	for _, th := range []struct {
+		name  string
+		value json.Number
+	}{{"ok", t.Ok}, {"warning", t.Warning}, {"critical", t.Critical}} {
+		if th.value == "" {
+			continue
+		}
+		if old, ok := d.Get("thresholds." + th.name).(string); ok && sameNumber(old, th.value.String()) {
+			thresholds[th.name] = old
+		} else {
+			thresholds[th.name] = normalizeNumber(th.value.String())
+		}
+	}
+	d.Set("thresholds", thresholds)
+}
+
+// normalizeNumber returns s in canonical form, so 3, 3.0 and 3e0 are all 3.
+// Anything that is not a number is returned as it is, for validation to
+// reject.
+func normalizeNumber(s string) string {
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return s
+	}
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// normalizeNumberState is a StateFunc storing numbers in canonical form.
+func normalizeNumberState(v interface{}) string {
+	return normalizeNumber(v.(string))
+}
+
+// sameNumber reports whether a and b are the same number.
+func sameNumber(a, b string) bool {
+	x, err := strconv.ParseFloat(strings.TrimSpace(a), 64)
+	if err != nil {
+		return false
+	}
+	y, err := strconv.ParseFloat(strings.TrimSpace(b), 64)
+	return err == nil && x == y
+}
+
 func resourceDatadogGenericDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*datadog.Client)
+	client := meta.(*providerMeta).client
 
 	i, err := strconv.Atoi(d.Id())
 	if err != nil {
 		return err
 	}
 
-	if err = client.DeleteMonitor(i); err != nil {
+	// A monitor that is already gone needs no deleting.
+	if err = client.DeleteMonitor(i); err != nil && !datadog.IsNotFound(err) {
+		if depErr := checkCompositeDependents(client, i); depErr != nil {
+			return depErr
+		}
 		return err
 	}
 
 	return nil
 }
 
+// checkCompositeDependents returns an error naming the composite monitors
+// that use monitor id, which is why Datadog refuses to delete it. It is only
+// called once a delete failed, to explain why.
+func checkCompositeDependents(client *datadog.Client, id int) error {
+	monitors, err := client.GetMonitors()
+	if err != nil {
+		return nil
+	}
+
+	var dependents []string
+	for _, m := range monitors {
+		if m.Type != "composite" || m.Id == id {
+			continue
+		}
+		c, err := query.ParseComposite(m.Query)
+		if err != nil {
+			continue
+		}
+		for _, ref := range c.IDs() {
+			if ref == id {
+				dependents = append(dependents, fmt.Sprintf("%d (%q)", m.Id, m.Name))
+				break
+			}
+		}
+	}
+
+	if len(dependents) > 0 {
+		return fmt.Errorf("monitor %d is used by composite monitors %s, remove it from them before deleting it",
+			id, strings.Join(dependents, ", "))
+	}
+	return nil
+}
+
 func resourceDatadogGenericExists(d *schema.ResourceData, meta interface{}) (b bool, e error) {
 	// Exists - This is called to verify a resource still exists. It is called prior to Read,
 	// and lowers the burden of Read to be able to assume the resource exists.
-	client := meta.(*datadog.Client)
+	client := meta.(*providerMeta).client
 
-	// Workaround to handle upgrades from < 0.0.4
+	// IDs from before 0.0.4 join several monitors with "__". Exists runs
+	// before MigrateState rewrites them, so look for any of those monitors.
 	if strings.Contains(d.Id(), "__") {
-		return false, fmt.Errorf("Monitor ID contains __, which is pre v0.0.4 old behaviour.\n    You have the following options:\n" +
-			"    * Run https://github.com/ojongerius/terraform-provider-datadog/blob/master/scripts/migration_helper.py to generate a new statefile and clean up monitors\n" +
-			"    * Mannualy fix this by deleting all your metric_check resources and recreate them, " +
-			"or manually remove half of the resources and hack the state file.\n")
+		ids, err := parseLegacyMonitorID(d.Id())
+		if err != nil {
+			return false, err
+		}
+		kept, _, err := findLegacyMonitors(client, ids)
+		return kept != 0, err
 	}
 
 	i, err := strconv.Atoi(d.Id())
@@ -87,7 +274,7 @@ func resourceDatadogGenericExists(d *schema.ResourceData, meta interface{}) (b b
 	}
 
 	if _, err = client.GetMonitor(i); err != nil {
-		if strings.Contains(err.Error(), "404 Not Found") {
+		if datadog.IsNotFound(err) {
 			return false, nil
 		}
 		return false, err
@@ -96,32 +283,275 @@ func resourceDatadogGenericExists(d *schema.ResourceData, meta interface{}) (b b
 	return true, nil
 }
 
-func resourceDatadogGenericRead(d *schema.ResourceData, meta interface{}) error {
-	// TODO: Add support for read function.
-	/* Read - This is called to resync the local state with the remote state.
-	Terraform guarantees that an existing ID will be set. This ID should be
-	used to look up the resource. Any remote data should be updated into the
-	local data. No changes to the remote resource are to be made.
-	*/
+// readMonitor fetches the monitor behind d for a Read. It returns nil, and
+// removes d from state, when the monitor was deleted outside Terraform.
+func readMonitor(d *schema.ResourceData, meta interface{}) (*datadog.Monitor, error) {
+	client := meta.(*providerMeta).client
 
-	return nil
+	i, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := client.GetMonitor(i)
+	if datadog.IsNotFound(err) {
+		log.Printf("[WARN] monitor %d not found, removing from state", i)
+		d.SetId("")
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading monitor %d: %s", i, err)
+	}
+
+	return m, nil
+}
+
+// monitorOptions returns every option of o that a monitor schema can expose,
+// keyed by attribute. Options left out of the response have Datadog's default
+// value.
+func monitorOptions(o datadog.Options) map[string]interface{} {
+	options := map[string]interface{}{
+		"notify_no_data":      o.NotifyNoData != nil && *o.NotifyNoData,
+		"no_data_timeframe":   0,
+		"renotify_interval":   0,
+		"notify_audit":        o.NotifyAudit != nil && *o.NotifyAudit,
+		"timeout_h":           0,
+		"escalation_message":  "",
+		"include_tags":        o.IncludeTags == nil || *o.IncludeTags,
+		"require_full_window": o.RequireFullWindow == nil || *o.RequireFullWindow,
+		"new_host_delay":      300,
+		"evaluation_delay":    0,
+		"locked":              o.Locked != nil && *o.Locked,
+		"notify_by":           []string{},
+	}
+	if o.NoDataTimeframe != nil {
+		options["no_data_timeframe"] = *o.NoDataTimeframe
+	}
+	if o.RenotifyInterval != nil {
+		options["renotify_interval"] = *o.RenotifyInterval
+	}
+	if o.TimeoutH != nil {
+		options["timeout_h"] = *o.TimeoutH
+	}
+	if o.EscalationMessage != nil {
+		options["escalation_message"] = *o.EscalationMessage
+	}
+	if o.NewHostDelay != nil {
+		options["new_host_delay"] = *o.NewHostDelay
+	}
+	if o.EvaluationDelay != nil {
+		options["evaluation_delay"] = *o.EvaluationDelay
+	}
+	if o.NotifyBy != nil {
+		options["notify_by"] = *o.NotifyBy
+	}
+
+	windows := make(map[string]string)
+	if w := o.ThresholdWindows; w != nil {
+		if w.TriggerWindow != "" {
+			windows["trigger_window"] = w.TriggerWindow
+		}
+		if w.RecoveryWindow != "" {
+			windows["recovery_window"] = w.RecoveryWindow
+		}
+	}
+	options["threshold_windows"] = windows
+
+	return options
+}
+
+// buildMonitorOptions returns the options every monitor resource has, from d.
+func buildMonitorOptions(d *schema.ResourceData) datadog.Options {
+	notifyNoData := d.Get("notify_no_data").(bool)
+	renotifyInterval := d.Get("renotify_interval").(int)
+	o := datadog.Options{
+		NotifyNoData:     &notifyNoData,
+		RenotifyInterval: &renotifyInterval,
+		Silenced:         make(map[string]int),
+	}
+	// Without a timeframe Datadog uses twice the window of the query.
+	if v := d.Get("no_data_timeframe").(int); v != 0 {
+		o.NoDataTimeframe = &v
+	}
+	return o
+}
+
+// readMonitorOptions sets the name, message and each option in schema s of a
+// monitor resource from m, so options changed in the UI show up as drift.
+func readMonitorOptions(d *schema.ResourceData, s map[string]*schema.Schema, m *datadog.Monitor) {
+	d.Set("name", m.Name)
+	d.Set("message", m.Message)
+	for k, v := range monitorOptions(m.Options) {
+		if _, ok := s[k]; ok {
+			d.Set(k, v)
+		}
+	}
+}
+
+// getStringList returns the strings in the list attribute key of d.
+func getStringList(d *schema.ResourceData, key string) []string {
+	var list []string
+	for _, v := range d.Get(key).([]interface{}) {
+		list = append(list, v.(string))
+	}
+	return list
 }
 
+// readScope sets the tags attribute of d from the scope of a query. A scope of
+// only "*" is every host, which a config writes as no tags at all, so it is
+// read as an empty list unless the state has it as "*".
+func readScope(d *schema.ResourceData, scope []string) {
+	if len(scope) == 1 && scope[0] == "*" {
+		if tags := getStringList(d, "tags"); len(tags) == 1 && tags[0] == "*" {
+			return
+		}
+		scope = []string{}
+	}
+	d.Set("tags", scope)
+}
+
+// monitorCreator creates m and stores its ID in d.
+//
+// The client does not retry a POST after a network or server error, as the
+// monitor might have been created even though the request failed. So every
+// create is sent with a marker tag unique to it, and when a create fails we
+// look for a monitor with that marker before posting again, and adopt it if
+// there is one. Only monitors this create made carry the marker, so one made
+// by hand is never taken over. A create that goes through first time costs
+// one request: its marker stays until the next update, and resourceTags
+// leaves it out of the state.
 func monitorCreator(d *schema.ResourceData, meta interface{}, m *datadog.Monitor) error {
-	client := meta.(*datadog.Client)
+	client := meta.(*providerMeta).client
+	m.Tags = mergeTags(meta.(*providerMeta).defaultTags, m.Tags)
 
-	m, err := client.CreateMonitor(m)
+	marker, err := newCreateMarker()
 	if err != nil {
-		return fmt.Errorf("error updating montor: %s", err.Error())
+		return fmt.Errorf("error creating monitor: %s", err.Error())
+	}
+	marked := *m
+	marked.Tags = append(append([]string{}, m.Tags...), marker)
+
+	id, attempt := 0, 0
+	for ; id == 0; attempt++ {
+		created, err := client.CreateMonitor(&marked)
+		if err == nil {
+			id = created.Id
+			break
+		}
+
+		if !datadog.IsRetryable(err) || attempt >= client.MaxRetries {
+			return fmt.Errorf("error creating monitor: %s", err.Error())
+		}
+		log.Printf("[WARN] creating monitor %q failed, checking whether it exists before retrying: %s", m.Name, err)
+
+		time.Sleep(createRetryWait(client, attempt))
+
+		found, err := findCreatedMonitors(client, m.Name, marker)
+		if err != nil && !datadog.IsRetryable(err) {
+			return fmt.Errorf("error creating monitor: %s", err.Error())
+		}
+		if err != nil {
+			// The lookup is retried with the create. Should both the
+			// failed create and the next one go through, the duplicate
+			// carries the marker too and is removed below.
+			log.Printf("[WARN] looking for monitor %q failed, retrying: %s", m.Name, err)
+			continue
+		}
+		if len(found) > 0 {
+			log.Printf("[INFO] adopting monitor %d created by an earlier attempt", found[0].Id)
+			id = found[0].Id
+		}
+	}
+	d.SetId(strconv.Itoa(id))
+
+	if attempt == 0 {
+		return nil
+	}
+	return cleanUpCreate(client, m, id, marker)
+}
+
+// cleanUpCreate deletes the duplicates of monitor id that failed attempts to
+// create it left behind, and removes the marker from it.
+func cleanUpCreate(client *datadog.Client, m *datadog.Monitor, id int, marker string) error {
+	found, err := findCreatedMonitors(client, m.Name, marker)
+	if err != nil {
+		return fmt.Errorf("error looking for duplicates of monitor %d: %s", id, err.Error())
+	}
+	for _, dup := range found {
+		if dup.Id == id {
+			continue
+		}
+		log.Printf("[INFO] deleting monitor %d, a duplicate of monitor %d created by an earlier attempt", dup.Id, id)
+		if err := client.DeleteMonitor(dup.Id); err != nil && !datadog.IsNotFound(err) {
+			return fmt.Errorf("error deleting monitor %d, a duplicate of monitor %d: %s", dup.Id, id, err.Error())
+		}
 	}
 
-	d.SetId(strconv.Itoa(m.Id))
+	// The marker is left out of the state, so one left behind does no harm
+	// and goes with the next update.
+	m.Id = id
+	if err := client.UpdateMonitor(m); err != nil {
+		log.Printf("[WARN] removing the create marker from monitor %d failed: %s", id, err)
+	}
 
 	return nil
 }
 
-func monitorUpdater(d *schema.ResourceData, meta interface{}, m *datadog.Monitor) error {
-	client := meta.(*datadog.Client)
+// createMarkerPrefix starts the tag that marks a monitor being created.
+const createMarkerPrefix = "terraform-create:"
+
+// newCreateMarker returns a tag unique to one create.
+func newCreateMarker() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return createMarkerPrefix + hex.EncodeToString(b), nil
+}
+
+// createRetryWait returns how long to wait before the given create retry,
+// doubling from half a second up to the client's MaxRetryWait.
+func createRetryWait(client *datadog.Client, attempt int) time.Duration {
+	wait := 500 * time.Millisecond << uint(attempt)
+	if client.MaxRetryWait > 0 && (wait > client.MaxRetryWait || wait <= 0) {
+		wait = client.MaxRetryWait
+	}
+	return wait
+}
+
+// findCreatedMonitors returns the monitors named name that carry marker, in
+// the order they were created.
+func findCreatedMonitors(client *datadog.Client, name, marker string) ([]datadog.Monitor, error) {
+	monitors, err := client.GetMonitorsByName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var found []datadog.Monitor
+	for _, e := range monitors {
+		for _, t := range e.Tags {
+			if t == marker {
+				found = append(found, e)
+				break
+			}
+		}
+	}
+	sort.Sort(monitorsByID(found))
+	return found, nil
+}
+
+// monitorsByID sorts monitors by ID, which is the order they were created in.
+type monitorsByID []datadog.Monitor
+
+func (m monitorsByID) Len() int           { return len(m) }
+func (m monitorsByID) Less(i, j int) bool { return m[i].Id < m[j].Id }
+func (m monitorsByID) Swap(i, j int)      { m[i], m[j] = m[j], m[i] }
+
+// monitorUpdater replaces the monitor behind d with m. Mutes on scopes the
+// resource does not own, those not in owned, are kept.
+func monitorUpdater(d *schema.ResourceData, meta interface{}, m *datadog.Monitor, owned map[string]bool) error {
+	client := meta.(*providerMeta).client
+	m.Tags = mergeTags(meta.(*providerMeta).defaultTags, m.Tags)
 
 	i, err := strconv.Atoi(d.Id())
 	if err != nil {
@@ -130,9 +560,129 @@ func monitorUpdater(d *schema.ResourceData, meta interface{}, m *datadog.Monitor
 
 	m.Id = i
 
+	if err = keepMutes(client, m, owned); err != nil {
+		return fmt.Errorf("error updating montor: %s", err.Error())
+	}
+
 	if err = client.UpdateMonitor(m); err != nil {
 		return fmt.Errorf("error updating montor: %s", err.Error())
 	}
 
 	return nil
 }
+
+// keepMutes adds the mutes of the monitor in Datadog that are not in owned to
+// m. An update replaces the whole monitor, which would otherwise unmute scopes
+// muted by datadog_monitor_mute or by hand.
+func keepMutes(client *datadog.Client, m *datadog.Monitor, owned map[string]bool) error {
+	current, err := client.GetMonitor(m.Id)
+	if err != nil {
+		return err
+	}
+
+	if m.Options.Silenced == nil {
+		m.Options.Silenced = make(map[string]int)
+	}
+	for scope, end := range current.Options.Silenced {
+		if !owned[scope] {
+			m.Options.Silenced[scope] = end
+		}
+	}
+
+	return nil
+}
+
+// mergeTags returns the provider's default tags combined with a resource's own
+// tags. A resource tag replaces a default tag with the same key, the part
+// before the first ":". The result is never nil, which would be sent to
+// Datadog as "tags": null.
+func mergeTags(defaults, tags []string) []string {
+	if len(defaults) == 0 {
+		if tags == nil {
+			return []string{}
+		}
+		return tags
+	}
+
+	keys := make(map[string]bool)
+	for _, t := range tags {
+		keys[tagKey(t)] = true
+	}
+
+	merged := make([]string, 0, len(defaults)+len(tags))
+	for _, t := range defaults {
+		if !keys[tagKey(t)] {
+			merged = append(merged, t)
+		}
+	}
+	return append(merged, tags...)
+}
+
+// resourceTags returns the tags read from a monitor that belong to the
+// resource rather than to the provider's default tags. Tags the resource sets
+// itself are kept even when they equal a default tag, create markers are not.
+func resourceTags(tags, defaults, own []string) []string {
+	owned := make(map[string]bool)
+	for _, t := range own {
+		owned[t] = true
+	}
+	isDefault := make(map[string]bool)
+	for _, t := range defaults {
+		isDefault[t] = true
+	}
+
+	result := []string{}
+	for _, t := range tags {
+		if strings.HasPrefix(t, createMarkerPrefix) {
+			continue
+		}
+		if owned[t] || !isDefault[t] {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+func tagKey(tag string) string {
+	return strings.SplitN(tag, ":", 2)[0]
+}
+
+func validateRFC3339(v interface{}, k string) (ws []string, es []error) {
+	if _, err := time.Parse(time.RFC3339, v.(string)); err != nil {
+		es = append(es, fmt.Errorf("%q must be an RFC3339 time such as 2016-01-02T15:04:05Z, got %q", k, v))
+	}
+	return
+}
+
+// getTime returns the RFC3339 time in key as seconds since the epoch, or 0
+// when it is not set.
+func getTime(d *schema.ResourceData, key string) (int64, error) {
+	v := d.Get(key).(string)
+	if v == "" {
+		return 0, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return 0, err
+	}
+	return t.Unix(), nil
+}
+
+// setTime sets key to the time sec seconds since the epoch, in RFC3339 and
+// UTC, or empty when sec is 0. A time in state that is the same instant is
+// kept as written, so a different time zone is not a change.
+func setTime(d *schema.ResourceData, key string, sec int64) {
+	old, err := time.Parse(time.RFC3339, d.Get(key).(string))
+	switch {
+	case sec == 0:
+		d.Set(key, "")
+	case err == nil && old.Unix() == sec:
+	default:
+		d.Set(key, formatTime(sec))
+	}
+}
+
+// formatTime returns the time sec seconds since the epoch in RFC3339 and UTC.
+func formatTime(sec int64) string {
+	return time.Unix(sec, 0).UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,621 @@
+package datadog
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/zorkian/go-datadog-api"
+)
+
+// flakyMonitorServer stores monitors, and drops the connection of the first
+// dropCreates POSTs. With storeDropped, dropped creates still store the
+// monitor, like a request that timed out after reaching Datadog. The first
+// failLookups searches by name and failUpdates updates fail with a 503.
+// requests counts the requests by method.
+type flakyMonitorServer struct {
+	sync.Mutex
+	dropCreates  int
+	storeDropped bool
+	failLookups  int
+	failUpdates  int
+	monitors     []datadog.Monitor
+	nextID       int
+	requests     map[string]int
+}
+
+func (s *flakyMonitorServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.requests == nil {
+		s.requests = make(map[string]int)
+	}
+	s.requests[r.Method]++
+
+	switch {
+	case r.Method == "POST" && r.URL.Path == "/api/v1/monitor":
+		var m datadog.Monitor
+		if err := json.NewDecoder(r.Body).Decode(&m); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		drop := s.dropCreates > 0
+		if !drop || s.storeDropped {
+			m.Id = s.store()
+			s.monitors = append(s.monitors, m)
+		}
+		if drop {
+			s.dropCreates--
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			conn.Close()
+			return
+		}
+		json.NewEncoder(w).Encode(m)
+	case r.Method == "GET" && r.URL.Path == "/api/v1/monitor":
+		if s.failLookups > 0 {
+			s.failLookups--
+			http.Error(w, `{"errors": ["unavailable"]}`, http.StatusServiceUnavailable)
+			return
+		}
+		found := []datadog.Monitor{}
+		for _, m := range s.monitors {
+			if strings.Contains(m.Name, r.URL.Query().Get("name")) {
+				found = append(found, m)
+			}
+		}
+		json.NewEncoder(w).Encode(found)
+	case r.Method == "PUT" && s.failUpdates > 0:
+		s.failUpdates--
+		http.Error(w, `{"errors": ["unavailable"]}`, http.StatusServiceUnavailable)
+	case r.Method == "PUT" || r.Method == "DELETE":
+		id, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/api/v1/monitor/"))
+		for i, m := range s.monitors {
+			if m.Id != id {
+				continue
+			}
+			if r.Method == "DELETE" {
+				s.monitors = append(s.monitors[:i], s.monitors[i+1:]...)
+				w.Write([]byte("{}"))
+				return
+			}
+			json.NewDecoder(r.Body).Decode(&s.monitors[i])
+			s.monitors[i].Id = id
+			json.NewEncoder(w).Encode(s.monitors[i])
+			return
+		}
+		http.NotFound(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// store returns the ID of a new monitor.
+func (s *flakyMonitorServer) store() int {
+	if s.nextID == 0 {
+		s.nextID = len(s.monitors) + 1
+	}
+	s.nextID++
+	return s.nextID - 1
+}
+
+func TestMonitorCreator_Retry(t *testing.T) {
+	cases := []struct {
+		Name         string
+		DropCreates  int
+		StoreDropped bool
+		FailLookups  int
+		FailUpdates  int
+		Err          bool
+	}{
+		{"no failures", 0, false, 0, 0, false},
+		{"dropped before create", 1, false, 0, 0, false},
+		{"dropped after create", 1, true, 0, 0, false},
+		{"dropped after create twice", 2, true, 0, 0, false},
+		// The client retries the lookup twice itself, the third failure
+		// reaches monitorCreator, which posts again and removes the
+		// duplicate.
+		{"lookup fails", 1, true, 3, 0, false},
+		// A marker left behind is not an error.
+		{"marker removal fails", 1, true, 0, 3, false},
+		{"budget runs out", 5, false, 0, 0, true},
+	}
+
+	for _, tc := range cases {
+		s := &flakyMonitorServer{dropCreates: tc.DropCreates, storeDropped: tc.StoreDropped, failLookups: tc.FailLookups,
+			failUpdates: tc.FailUpdates}
+		ts := httptest.NewServer(s)
+
+		c := Config{APIKey: "foo", APPKey: "bar", APIURL: ts.URL, MaxRetries: 2, MaxRetryWait: 10 * time.Millisecond}
+		client, err := c.Client()
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		d := resourceDatadogMonitor().TestResourceData()
+		m := &datadog.Monitor{Name: "foo", Type: "metric alert", Query: "avg(last_1h):avg:aws.ec2.cpu{*} > 2",
+			Tags: []string{"team:sre"}}
+		err = monitorCreator(d, &providerMeta{client: client}, m)
+		ts.Close()
+
+		if (err != nil) != tc.Err {
+			t.Fatalf("%s: expected error %t, got %v", tc.Name, tc.Err, err)
+		}
+		if tc.Err {
+			continue
+		}
+		if len(s.monitors) != 1 {
+			t.Fatalf("%s: expected exactly 1 monitor, got %d", tc.Name, len(s.monitors))
+		}
+		if d.Id() != strconv.Itoa(s.monitors[0].Id) {
+			t.Fatalf("%s: expected ID %d, got %s", tc.Name, s.monitors[0].Id, d.Id())
+		}
+
+		// A create that goes through first time is one request, and
+		// keeps its marker. Otherwise the marker is removed if it can be.
+		tags := s.monitors[0].Tags
+		if tc.DropCreates == 0 && (s.requests["POST"] != 1 || s.requests["GET"] != 0 || s.requests["PUT"] != 0) {
+			t.Fatalf("%s: expected a single POST, got %v", tc.Name, s.requests)
+		}
+		if tc.DropCreates == 0 || tc.FailUpdates > 0 {
+			if len(tags) != 2 || !strings.HasPrefix(tags[1], createMarkerPrefix) {
+				t.Fatalf("%s: expected the create marker after team:sre, tags are %v", tc.Name, tags)
+			}
+			continue
+		}
+		if !reflect.DeepEqual(tags, []string{"team:sre"}) {
+			t.Fatalf("%s: expected the create marker to be removed, tags are %v", tc.Name, tags)
+		}
+	}
+}
+
+func TestMonitorCreator_IgnoresMonitorsMadeByHand(t *testing.T) {
+	m := datadog.Monitor{Id: 1, Name: "foo", Type: "metric alert", Query: "avg(last_1h):avg:aws.ec2.cpu{*} > 2"}
+	s := &flakyMonitorServer{dropCreates: 1, monitors: []datadog.Monitor{m}}
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	c := Config{APIKey: "foo", APPKey: "bar", APIURL: ts.URL, MaxRetries: 2, MaxRetryWait: 10 * time.Millisecond}
+	client, err := c.Client()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	d := resourceDatadogMonitor().TestResourceData()
+	m.Id = 0
+	if err := monitorCreator(d, &providerMeta{client: client}, &m); err != nil {
+		t.Fatal(err)
+	}
+	if d.Id() == "1" || len(s.monitors) != 2 {
+		t.Fatalf("Expected a monitor of its own rather than the one made by hand, got %s of %d", d.Id(), len(s.monitors))
+	}
+}
+
+func TestMonitorDeletedOutsideTerraform(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"errors": ["Monitor not found"]}`))
+	}))
+	defer ts.Close()
+
+	c := Config{APIKey: "foo", APPKey: "bar", APIURL: ts.URL}
+	client, err := c.Client()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	_, err = client.GetMonitor(1)
+	apiErr, ok := err.(*datadog.APIError)
+	if !ok {
+		t.Fatalf("Expected an *APIError, got %#v", err)
+	}
+	if !datadog.IsNotFound(err) || datadog.IsRetryable(err) || datadog.IsUnauthorized(err) {
+		t.Fatalf("Expected a permanent not found error, got %#v", apiErr)
+	}
+	if len(apiErr.Errors) != 1 || apiErr.Errors[0] != "Monitor not found" {
+		t.Fatalf("Expected the errors array to be parsed, got %#v", apiErr.Errors)
+	}
+
+	meta := &providerMeta{client: client}
+	d := resourceDatadogMonitor().TestResourceData()
+	d.SetId("1")
+
+	exists, err := resourceDatadogGenericExists(d, meta)
+	if err != nil || exists {
+		t.Fatalf("Expected Exists to return false without error, got %t, %v", exists, err)
+	}
+	if err := resourceDatadogMonitorRead(d, meta); err != nil {
+		t.Fatalf("Expected Read to succeed, got %s", err)
+	}
+	if d.Id() != "" {
+		t.Fatalf("Expected Read to remove the monitor from state, ID is %q", d.Id())
+	}
+
+	d.SetId("1")
+	if err := resourceDatadogGenericDelete(d, meta); err != nil {
+		t.Fatalf("Expected deleting a missing monitor to succeed, got %s", err)
+	}
+}
+
+func TestAPIErrorHelpers(t *testing.T) {
+	cases := []struct {
+		Status       int
+		NotFound     bool
+		RateLimited  bool
+		Unauthorized bool
+		Retryable    bool
+	}{
+		{400, false, false, false, false},
+		{401, false, false, true, false},
+		{403, false, false, true, false},
+		{404, true, false, false, false},
+		{429, false, true, false, true},
+		{500, false, false, false, true},
+		{503, false, false, false, true},
+	}
+
+	for _, tc := range cases {
+		err := &datadog.APIError{StatusCode: tc.Status}
+		if datadog.IsNotFound(err) != tc.NotFound ||
+			datadog.IsRateLimited(err) != tc.RateLimited ||
+			datadog.IsUnauthorized(err) != tc.Unauthorized ||
+			datadog.IsRetryable(err) != tc.Retryable {
+			t.Fatalf("Unexpected helper results for status %d", tc.Status)
+		}
+	}
+}
+
+func TestMergeTags(t *testing.T) {
+	cases := []struct {
+		Defaults []string
+		Tags     []string
+		Expected []string
+	}{
+		{nil, []string{"foo:bar"}, []string{"foo:bar"}},
+		{nil, nil, []string{}},
+		{[]string{"team:a", "env:prod"}, nil, []string{"team:a", "env:prod"}},
+		{[]string{"team:a", "env:prod"}, []string{"foo:bar"}, []string{"team:a", "env:prod", "foo:bar"}},
+		{[]string{"team:a", "env:prod"}, []string{"env:dev"}, []string{"team:a", "env:dev"}},
+		{[]string{"critical"}, []string{"critical"}, []string{"critical"}},
+	}
+
+	for _, tc := range cases {
+		if v := mergeTags(tc.Defaults, tc.Tags); !reflect.DeepEqual(v, tc.Expected) {
+			t.Fatalf("mergeTags(%v, %v): expected %v, got %v", tc.Defaults, tc.Tags, tc.Expected, v)
+		}
+	}
+}
+
+func TestResourceTags(t *testing.T) {
+	cases := []struct {
+		Tags     []string
+		Defaults []string
+		Own      []string
+		Expected []string
+	}{
+		{[]string{"foo:bar"}, nil, nil, []string{"foo:bar"}},
+		{[]string{"team:a", "env:prod", "foo:bar"}, []string{"team:a", "env:prod"}, []string{"foo:bar"}, []string{"foo:bar"}},
+		{[]string{"team:a", "env:dev"}, []string{"team:a", "env:prod"}, []string{"env:dev"}, []string{"env:dev"}},
+		{[]string{"team:a", "env:prod"}, []string{"team:a", "env:prod"}, []string{"env:prod"}, []string{"env:prod"}},
+		{[]string{"team:a", "added:in-ui"}, []string{"team:a"}, nil, []string{"added:in-ui"}},
+		{[]string{"foo:bar", createMarkerPrefix + "0123456789abcdef"}, nil, []string{"foo:bar"}, []string{"foo:bar"}},
+	}
+
+	for _, tc := range cases {
+		if v := resourceTags(tc.Tags, tc.Defaults, tc.Own); !reflect.DeepEqual(v, tc.Expected) {
+			t.Fatalf("resourceTags(%v, %v, %v): expected %v, got %v", tc.Tags, tc.Defaults, tc.Own, tc.Expected, v)
+		}
+	}
+}
+
+func TestLegacyMonitorRead(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Resource *schema.Resource
+		Query    string
+		State    map[string]interface{}
+		Expected map[string]string
+		Err      bool
+	}{
+		{
+			"metric alert",
+			resourceDatadogMetricAlert(),
+			"avg(last_1h):sum:aws.ec2.cpu{environment:foo,host:foo} by {host} >= 2",
+			nil,
+			map[string]string{"time_aggr": "avg", "time_window": "last_1h", "space_aggr": "sum",
+				"metric": "aws.ec2.cpu", "tags.#": "2", "tags.1": "host:foo", "keys.#": "1", "keys.0": "host",
+				"operator": ">=", "thresholds.critical": "2"},
+			false,
+		},
+		{
+			"metric alert without keys",
+			resourceDatadogMetricAlert(),
+			"avg(last_5m):avg:aws.ec2.cpu{*}  < 2",
+			nil,
+			map[string]string{"tags.#": "0", "keys.#": "0", "operator": "<"},
+			false,
+		},
+		{
+			"metric alert with tags set to *",
+			resourceDatadogMetricAlert(),
+			"avg(last_5m):avg:aws.ec2.cpu{*} < 2",
+			map[string]interface{}{"tags": []string{"*"}},
+			map[string]string{"tags.#": "1", "tags.0": "*", "time_window": "last_5m"},
+			false,
+		},
+		{
+			"metric alert with the threshold formatted differently",
+			resourceDatadogMetricAlert(),
+			"avg(last_1h):avg:aws.ec2.cpu{host:foo} > 2.0",
+			map[string]interface{}{"time_aggr": "avg", "time_window": "last_1h", "space_aggr": "avg",
+				"metric": "aws.ec2.cpu", "tags": []string{"*", "host:foo"}, "operator": ">",
+				"thresholds": map[string]string{"critical": "2"}},
+			map[string]string{"tags.#": "2", "tags.0": "*"},
+			false,
+		},
+		{
+			"metric alert with query",
+			resourceDatadogMetricAlert(),
+			"avg(last_1h):avg:aws.ec2.cpu{*} - avg(last_1h):avg:aws.ec2.mem{*} != 2",
+			map[string]interface{}{"query": "avg(last_1h):avg:aws.ec2.cpu{*}"},
+			map[string]string{"query": "avg(last_1h):avg:aws.ec2.cpu{*} - avg(last_1h):avg:aws.ec2.mem{*}",
+				"operator": "!="},
+			false,
+		},
+		{
+			"metric alert changed to a custom query",
+			resourceDatadogMetricAlert(),
+			"avg(last_1h):avg:aws.ec2.cpu{*} - avg(last_1h):avg:aws.ec2.mem{*} > 2",
+			nil,
+			nil,
+			true,
+		},
+		{
+			"outlier alert formatted differently",
+			resourceDatadogOutlierAlert(),
+			"avg(last_1h):outliers(avg:system.load.5{host:foo},'dbscan',2) > 0",
+			map[string]interface{}{"time_aggr": "avg", "time_window": "last_1h", "space_aggr": "avg",
+				"metric": "system.load.5", "tags": []string{"*", "host:foo"}, "algorithm": "dbscan", "threshold": "2"},
+			map[string]string{"tags.#": "2", "tags.0": "*"},
+			false,
+		},
+		{
+			"service check",
+			resourceDatadogServiceCheck(),
+			`"datadog.agent.up".over("environment:foo","host:bar").by("foo","bar").last(2).count_by_status()`,
+			nil,
+			map[string]string{"check": "datadog.agent.up", "tags.#": "2", "tags.0": "environment:foo",
+				"keys.#": "2", "keys.1": "bar"},
+			false,
+		},
+		{
+			"service check without keys",
+			resourceDatadogServiceCheck(),
+			`"datadog.agent.up".over("*").last(2).count_by_status()`,
+			nil,
+			map[string]string{"tags.#": "0", "keys.#": "0"},
+			false,
+		},
+		{
+			"service check changed to a different query",
+			resourceDatadogServiceCheck(),
+			`"datadog.agent.up".over("*").exclude("host:foo").last(2).count_by_status()`,
+			nil,
+			nil,
+			true,
+		},
+		{
+			"outlier alert",
+			resourceDatadogOutlierAlert(),
+			"avg(last_1h):outliers(avg:system.load.5{environment:foo,host:foo} by {host}, 'mad',2.5) > 0",
+			nil,
+			map[string]string{"metric": "system.load.5", "tags.#": "2", "keys.0": "host",
+				"algorithm": "mad", "threshold": "2.5"},
+			false,
+		},
+		{
+			"outlier alert changed to a metric alert",
+			resourceDatadogOutlierAlert(),
+			"avg(last_1h):avg:system.load.5{*} > 2",
+			nil,
+			nil,
+			true,
+		},
+	}
+
+	for _, tc := range cases {
+		renotifyInterval := 60
+		m := datadog.Monitor{Id: 1, Name: "foo", Message: "bar", Query: tc.Query}
+		m.Options.RenotifyInterval = &renotifyInterval
+		m.Options.Thresholds.Critical = "2"
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(m)
+		}))
+
+		c := Config{APIKey: "foo", APPKey: "bar", APIURL: ts.URL}
+		client, err := c.Client()
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		d := tc.Resource.TestResourceData()
+		d.SetId("1")
+		for k, v := range tc.State {
+			d.Set(k, v)
+		}
+		err = tc.Resource.Read(d, &providerMeta{client: client})
+		ts.Close()
+
+		if (err != nil) != tc.Err {
+			t.Fatalf("%s: expected error %t, got %v", tc.Name, tc.Err, err)
+		}
+		if tc.Err {
+			if !strings.Contains(err.Error(), strconv.Quote(tc.Query)) {
+				t.Fatalf("%s: expected the error to name the query, got %s", tc.Name, err)
+			}
+			continue
+		}
+
+		attributes := d.State().Attributes
+		if attributes["name"] != "foo" || attributes["renotify_interval"] != "60" {
+			t.Fatalf("%s: expected the options to be read, got %v", tc.Name, attributes)
+		}
+		for k, v := range tc.Expected {
+			if attributes[k] != v {
+				t.Fatalf("%s: expected %s to be %q, got %q", tc.Name, k, v, attributes[k])
+			}
+		}
+	}
+}
+
+func TestReadMonitorOptions(t *testing.T) {
+	notifyNoData, notifyAudit, includeTags, requireFullWindow, locked := false, true, false, false, true
+	noDataTimeframe, renotifyInterval, timeoutH, newHostDelay, evaluationDelay := 20, 60, 2, 600, 900
+	escalationMessage := "still failing"
+	notifyBy := []string{"host"}
+	m := datadog.Monitor{Id: 1, Name: "foo", Message: "bar", Type: "metric alert"}
+	m.Options = datadog.Options{
+		NotifyNoData:      &notifyNoData,
+		NoDataTimeframe:   &noDataTimeframe,
+		RenotifyInterval:  &renotifyInterval,
+		NotifyAudit:       &notifyAudit,
+		TimeoutH:          &timeoutH,
+		EscalationMessage: &escalationMessage,
+		IncludeTags:       &includeTags,
+		RequireFullWindow: &requireFullWindow,
+		NewHostDelay:      &newHostDelay,
+		EvaluationDelay:   &evaluationDelay,
+		Locked:            &locked,
+		NotifyBy:          &notifyBy,
+		ThresholdWindows:  &datadog.ThresholdWindows{TriggerWindow: "last_15m"},
+	}
+	m.Options.Thresholds.Critical = "2"
+
+	// Every option differs from its default, so each one read is a change.
+	expected := map[string]string{
+		"notify_no_data":                   "false",
+		"no_data_timeframe":                "20",
+		"renotify_interval":                "60",
+		"notify_audit":                     "true",
+		"timeout_h":                        "2",
+		"escalation_message":               "still failing",
+		"include_tags":                     "false",
+		"require_full_window":              "false",
+		"new_host_delay":                   "600",
+		"evaluation_delay":                 "900",
+		"locked":                           "true",
+		"notify_by.0":                      "host",
+		"threshold_windows.trigger_window": "last_15m",
+	}
+	// Attributes of datadog_monitor that are not options.
+	other := map[string]bool{"name": true, "message": true, "query": true, "type": true, "tags": true,
+		"thresholds": true, "silenced": true}
+
+	cases := []struct {
+		Resource *schema.Resource
+		Query    string
+	}{
+		{resourceDatadogMonitor(), "avg(last_1h):avg:aws.ec2.cpu{*} > 2"},
+		{resourceDatadogMetricAlert(), "avg(last_1h):avg:aws.ec2.cpu{*} > 2"},
+		{resourceDatadogServiceCheck(), `"datadog.agent.up".over("*").last(2).count_by_status()`},
+		{resourceDatadogOutlierAlert(), "avg(last_1h):outliers(avg:system.load.5{*},'dbscan',2) > 0"},
+	}
+
+	for _, tc := range cases {
+		m.Query = tc.Query
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(m)
+		}))
+
+		c := Config{APIKey: "foo", APPKey: "bar", APIURL: ts.URL}
+		client, err := c.Client()
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		d := tc.Resource.TestResourceData()
+		d.SetId("1")
+		err = tc.Resource.Read(d, &providerMeta{client: client})
+		ts.Close()
+		if err != nil {
+			t.Fatalf("%s: err: %s", tc.Query, err)
+		}
+
+		attributes := d.State().Attributes
+		for k, s := range tc.Resource.Schema {
+			if s.Computed || other[k] {
+				continue
+			}
+			found := false
+			for attr, v := range expected {
+				if attr != k && !strings.HasPrefix(attr, k+".") {
+					continue
+				}
+				found = true
+				if attributes[attr] != v {
+					t.Fatalf("%s: expected option %s to be read as %q, got %q", tc.Query, attr, v, attributes[attr])
+				}
+			}
+			if !found && tc.Resource.Schema["type"] != nil {
+				t.Fatalf("Option %s of datadog_monitor is not covered", k)
+			}
+		}
+	}
+}
+
+func TestNormalizeNumber(t *testing.T) {
+	cases := []struct {
+		Value    string
+		Expected string
+	}{
+		{"3", "3"},
+		{"-3", "-3"},
+		{"3.0", "3"},
+		{"03", "3"},
+		{"0.50", "0.5"},
+		{"-0.25", "-0.25"},
+		{"1e3", "1000"},
+		{"2.5E-2", "0.025"},
+		{"1e+06", "1000000"},
+		{" 2 ", "2"},
+		{"high", "high"},
+	}
+
+	for _, tc := range cases {
+		if n := normalizeNumber(tc.Value); n != tc.Expected {
+			t.Fatalf("Expected %q to normalize to %q, got %q", tc.Value, tc.Expected, n)
+		}
+	}
+
+	if !sameNumber("3", "3.0") || !sameNumber("1e3", "1000") || sameNumber("3", "3.5") || sameNumber("high", "high") {
+		t.Fatalf("Unexpected sameNumber results")
+	}
+}
+
+func TestReadThresholds(t *testing.T) {
+	d := resourceDatadogMonitor().TestResourceData()
+	d.Set("thresholds", map[string]string{"ok": "0.0", "warning": "1", "critical": "3e0"})
+
+	readThresholds(d, datadog.ThresholdCount{Ok: "0", Warning: "1.5", Critical: "3.0"})
+
+	expected := map[string]string{"ok": "0.0", "warning": "1.5", "critical": "3e0"}
+	for k, v := range expected {
+		if th := d.Get("thresholds." + k); th != v {
+			t.Fatalf("Expected threshold %s to be %q, got %q", k, v, th)
+		}
+	}
+
+	// Thresholds that are not in state yet are stored in canonical form.
+	d = resourceDatadogMonitor().TestResourceData()
+	readThresholds(d, datadog.ThresholdCount{Warning: "2.50", Critical: "1E1"})
+	if d.Get("thresholds.warning") != "2.5" || d.Get("thresholds.critical") != "10" {
+		t.Fatalf("Expected canonical thresholds, got %v", d.Get("thresholds"))
+	}
+}
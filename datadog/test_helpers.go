@@ -3,17 +3,27 @@ package datadog
 import (
 	"fmt"
 	"strconv"
-	"strings"
 
 	"github.com/hashicorp/terraform/terraform"
 	"github.com/zorkian/go-datadog-api"
 )
 
+// monitorResourceTypes are the resources whose ID is a monitor ID.
+var monitorResourceTypes = map[string]bool{
+	"datadog_monitor":       true,
+	"datadog_service_check": true,
+	"datadog_metric_alert":  true,
+	"datadog_outlier_alert": true,
+}
+
 func destroyHelper(s *terraform.State, client *datadog.Client) error {
 	for _, r := range s.RootModule().Resources {
+		if !monitorResourceTypes[r.Type] {
+			continue
+		}
 		i, _ := strconv.Atoi(r.Primary.ID)
 		if _, err := client.GetMonitor(i); err != nil {
-			if strings.Contains(err.Error(), "404 Not Found") {
+			if datadog.IsNotFound(err) {
 				continue
 			}
 			return fmt.Errorf("Received an error retrieving monitor %s", err)
@@ -25,6 +35,9 @@ func destroyHelper(s *terraform.State, client *datadog.Client) error {
 
 func existsHelper(s *terraform.State, client *datadog.Client) error {
 	for _, r := range s.RootModule().Resources {
+		if !monitorResourceTypes[r.Type] {
+			continue
+		}
 		i, _ := strconv.Atoi(r.Primary.ID)
 		if _, err := client.GetMonitor(i); err != nil {
 			return fmt.Errorf("Received an error retrieving monitor %s", err)
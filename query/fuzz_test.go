@@ -0,0 +1,60 @@
+package query
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// FuzzParse checks that any query that parses renders to a canonical form
+// that parses back to the same query.
+func FuzzParse(f *testing.F) {
+	addSeeds(f, "queries")
+	f.Fuzz(func(t *testing.T, s string) {
+		testRoundTrip(t, s, func(s string) (Query, error) { return Parse(s) })
+	})
+}
+
+func FuzzParseComparison(f *testing.F) {
+	addSeeds(f, "comparisons")
+	f.Fuzz(func(t *testing.T, s string) {
+		testRoundTrip(t, s, func(s string) (Query, error) { return ParseComparison(s) })
+	})
+}
+
+func testRoundTrip(t *testing.T, s string, parse func(string) (Query, error)) {
+	q, err := parse(s)
+	if err != nil {
+		return
+	}
+	rendered := q.String()
+	again, err := parse(rendered)
+	if err != nil {
+		t.Fatalf("%q parsed, but its canonical form %q does not: %s", s, rendered, err)
+	}
+	if !reflect.DeepEqual(q, again) {
+		t.Fatalf("%q parsed to %#v, but its canonical form %q parsed to %#v", s, q, rendered, again)
+	}
+	if again.String() != rendered {
+		t.Fatalf("Canonical form %q rendered again as %q", rendered, again.String())
+	}
+}
+
+// addSeeds adds the golden test inputs to the fuzz corpus.
+func addSeeds(f *testing.F, name string) {
+	file, err := os.Open(filepath.Join("testdata", name+".txt"))
+	if err != nil {
+		f.Fatalf("err: %s", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if line := scanner.Text(); line != "" && !strings.HasPrefix(line, "#") {
+			f.Add(line)
+		}
+	}
+}
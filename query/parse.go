@@ -0,0 +1,442 @@
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SyntaxError describes why a query could not be parsed.
+type SyntaxError struct {
+	Query  string
+	Offset int
+	Msg    string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("cannot parse query %q: %s at offset %d", e.Query, e.Msg, e.Offset)
+}
+
+// operators are the comparisons a monitor query can end in, longest first.
+var operators = []string{"<=", ">=", "==", "!=", "<", ">"}
+
+// Parse parses a metric, outlier, service check or composite query.
+func Parse(s string) (Query, error) {
+	trimmed := strings.TrimSpace(s)
+	if strings.HasPrefix(trimmed, `"`) {
+		return ParseServiceCheck(s)
+	}
+	if trimmed != "" && strings.IndexByte("0123456789(!", trimmed[0]) >= 0 {
+		return ParseComposite(s)
+	}
+	if strings.Contains(s, "outliers(") {
+		return ParseOutlier(s)
+	}
+	return ParseMetric(s)
+}
+
+// ParseMetric parses a metric alert query.
+func ParseMetric(s string) (*Metric, error) {
+	p := &parser{s: s}
+	q := &Metric{}
+	var err error
+
+	if q.TimeAggr, q.TimeWindow, err = p.timeAggr(); err != nil {
+		return nil, err
+	}
+	if q.SpaceAggr, q.Metric, q.Scope, q.By, err = p.metric(); err != nil {
+		return nil, err
+	}
+	if q.Operator, err = p.operator(); err != nil {
+		return nil, err
+	}
+	if q.Threshold, err = p.number(); err != nil {
+		return nil, err
+	}
+	return q, p.end()
+}
+
+// ParseOutlier parses an outlier alert query.
+func ParseOutlier(s string) (*Outlier, error) {
+	p := &parser{s: s}
+	q := &Outlier{}
+	var err error
+
+	if q.TimeAggr, q.TimeWindow, err = p.timeAggr(); err != nil {
+		return nil, err
+	}
+	if err = p.literal("outliers("); err != nil {
+		return nil, err
+	}
+	if q.SpaceAggr, q.Metric, q.Scope, q.By, err = p.metric(); err != nil {
+		return nil, err
+	}
+	if err = p.literal(","); err != nil {
+		return nil, err
+	}
+	start := p.pos
+	if q.Algorithm, err = p.quoted(); err != nil {
+		return nil, err
+	}
+	if !isIdentString(q.Algorithm) {
+		return nil, p.errorAt(start, "expected an algorithm name")
+	}
+	if err = p.literal(","); err != nil {
+		return nil, err
+	}
+	if q.Tolerance, err = p.number(); err != nil {
+		return nil, err
+	}
+	if err = p.literal(")"); err != nil {
+		return nil, err
+	}
+	if err = p.literal(">"); err != nil {
+		return nil, err
+	}
+	if err = p.literal("0"); err != nil {
+		return nil, err
+	}
+	return q, p.end()
+}
+
+// ParseServiceCheck parses a service check query.
+func ParseServiceCheck(s string) (*ServiceCheck, error) {
+	p := &parser{s: s}
+	q := &ServiceCheck{}
+	var err error
+
+	if q.Check, err = p.quoted(); err != nil {
+		return nil, err
+	}
+	if err = p.literal(".over("); err != nil {
+		return nil, err
+	}
+	if q.Scope, err = p.quotedList(); err != nil {
+		return nil, err
+	}
+	q.Scope = normalizeScope(q.Scope)
+	if p.accept(".by(") {
+		if q.By, err = p.quotedList(); err != nil {
+			return nil, err
+		}
+	}
+	if err = p.literal(".last("); err != nil {
+		return nil, err
+	}
+	start := p.pos
+	if q.Last, err = p.number(); err != nil {
+		return nil, err
+	}
+	if _, err := strconv.Atoi(q.Last); err != nil {
+		return nil, p.errorAt(start, "last needs a whole number of checks")
+	}
+	if err = p.literal(").count_by_status()"); err != nil {
+		return nil, err
+	}
+	return q, p.end()
+}
+
+// ParseComposite parses a composite monitor query. Monitor IDs are combined
+// with &&, || and !, and grouped with parentheses.
+func ParseComposite(s string) (*Composite, error) {
+	p := &parser{s: s}
+	q := &Composite{}
+	if err := p.compositeExpr(q); err != nil {
+		return nil, err
+	}
+	return q, p.end()
+}
+
+// compositeExpr reads terms joined by && or ||.
+func (p *parser) compositeExpr(q *Composite) error {
+	for {
+		if err := p.compositeTerm(q); err != nil {
+			return err
+		}
+		switch {
+		case p.accept("&&"):
+			q.Terms = append(q.Terms, "&&")
+		case p.accept("||"):
+			q.Terms = append(q.Terms, "||")
+		default:
+			return nil
+		}
+	}
+}
+
+// compositeTerm reads a monitor ID, a negated term or a group in parentheses.
+func (p *parser) compositeTerm(q *Composite) error {
+	switch {
+	case p.accept("!"):
+		q.Terms = append(q.Terms, "!")
+		return p.compositeTerm(q)
+	case p.accept("("):
+		q.Terms = append(q.Terms, "(")
+		if err := p.compositeExpr(q); err != nil {
+			return err
+		}
+		if err := p.literal(")"); err != nil {
+			return err
+		}
+		q.Terms = append(q.Terms, ")")
+		return nil
+	}
+
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.s) && p.s[p.pos] >= '0' && p.s[p.pos] <= '9' {
+		p.pos++
+	}
+	id, err := strconv.Atoi(p.s[start:p.pos])
+	if err != nil || id == 0 {
+		return p.errorAt(start, "expected a monitor ID")
+	}
+	q.Terms = append(q.Terms, strconv.Itoa(id))
+	return nil
+}
+
+// ParseComparison parses a query written by hand that ends in a comparison
+// against a threshold. Only the operator and threshold are looked at.
+func ParseComparison(s string) (*Comparison, error) {
+	rest := strings.TrimRight(s, " \t\n")
+	i := strings.LastIndexAny(rest, " \t\n<>=")
+	threshold := rest[i+1:]
+	if !isNumber(threshold) {
+		return nil, &SyntaxError{Query: s, Offset: i + 1, Msg: "expected a threshold"}
+	}
+
+	rest = strings.TrimRight(rest[:i+1], " \t\n")
+	for _, op := range operators {
+		if strings.HasSuffix(rest, op) {
+			expr := strings.TrimSpace(strings.TrimSuffix(rest, op))
+			if expr == "" {
+				return nil, &SyntaxError{Query: s, Offset: 0, Msg: "expected a query before " + op}
+			}
+			return &Comparison{Expr: expr, Operator: op, Threshold: number(threshold)}, nil
+		}
+	}
+	return nil, &SyntaxError{Query: s, Offset: len(rest), Msg: "expected an operator"}
+}
+
+type parser struct {
+	s   string
+	pos int
+}
+
+func (p *parser) errorAt(pos int, format string, args ...interface{}) error {
+	return &SyntaxError{Query: p.s, Offset: pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) skipSpace() {
+	for p.pos < len(p.s) && strings.IndexByte(" \t\n", p.s[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+// accept consumes lit if it comes next, ignoring space before it.
+func (p *parser) accept(lit string) bool {
+	p.skipSpace()
+	if strings.HasPrefix(p.s[p.pos:], lit) {
+		p.pos += len(lit)
+		return true
+	}
+	return false
+}
+
+func (p *parser) literal(lit string) error {
+	if !p.accept(lit) {
+		return p.errorAt(p.pos, "expected %q", lit)
+	}
+	return nil
+}
+
+func (p *parser) end() error {
+	p.skipSpace()
+	if p.pos != len(p.s) {
+		return p.errorAt(p.pos, "unexpected %q", p.s[p.pos:])
+	}
+	return nil
+}
+
+// ident reads a name made of letters, digits, "_" and ".".
+func (p *parser) ident(what string) (string, error) {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.s) && isIdent(p.s[p.pos]) {
+		p.pos++
+	}
+	if p.pos == start {
+		return "", p.errorAt(start, "expected %s", what)
+	}
+	return p.s[start:p.pos], nil
+}
+
+func isIdentString(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if !isIdent(s[i]) {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func isIdent(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '.'
+}
+
+func (p *parser) number() (string, error) {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.s) && strings.IndexByte(numberChars, p.s[p.pos]) >= 0 {
+		p.pos++
+	}
+	n := p.s[start:p.pos]
+	if !isNumber(n) {
+		return "", p.errorAt(start, "expected a number")
+	}
+	return number(n), nil
+}
+
+const numberChars = "0123456789+-.eE"
+
+// isNumber reports whether s is a decimal number, leaving out the hex, Inf and
+// NaN forms strconv also accepts.
+func isNumber(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if strings.IndexByte(numberChars, s[i]) < 0 {
+			return false
+		}
+	}
+	_, err := strconv.ParseFloat(s, 64)
+	return err == nil
+}
+
+func (p *parser) operator() (string, error) {
+	for _, op := range operators {
+		if p.accept(op) {
+			return op, nil
+		}
+	}
+	return "", p.errorAt(p.pos, "expected one of %s", strings.Join(operators, " "))
+}
+
+// timeAggr reads the leading aggr(window): of metric and outlier queries.
+func (p *parser) timeAggr() (aggr, window string, err error) {
+	if aggr, err = p.ident("a time aggregator"); err != nil {
+		return
+	}
+	if err = p.literal("("); err != nil {
+		return
+	}
+	if window, err = p.ident("a time window"); err != nil {
+		return
+	}
+	if err = p.literal(")"); err != nil {
+		return
+	}
+	err = p.literal(":")
+	return
+}
+
+// metric reads aggr:metric{scope} by {keys}, the by part being optional.
+func (p *parser) metric() (aggr, metric string, scope, by []string, err error) {
+	if aggr, err = p.ident("a space aggregator"); err != nil {
+		return
+	}
+	if err = p.literal(":"); err != nil {
+		return
+	}
+	if metric, err = p.ident("a metric name"); err != nil {
+		return
+	}
+	if err = p.literal("{"); err != nil {
+		return
+	}
+	if scope, err = p.list('}'); err != nil {
+		return
+	}
+	scope = normalizeScope(scope)
+	if p.accept("by") {
+		if err = p.literal("{"); err != nil {
+			return
+		}
+		if by, err = p.list('}'); err != nil {
+			return
+		}
+		if len(by) == 0 {
+			err = p.errorAt(p.pos, "expected at least one key")
+		}
+	}
+	return
+}
+
+// list reads a comma separated list of tags or keys up to close.
+func (p *parser) list(close byte) ([]string, error) {
+	start := p.pos
+	end := strings.IndexByte(p.s[start:], close)
+	if end < 0 {
+		return nil, p.errorAt(start, "expected %q", string(close))
+	}
+	p.pos = start + end + 1
+
+	var list []string
+	for _, v := range strings.Split(p.s[start:start+end], ",") {
+		v = strings.TrimSpace(v)
+		if strings.ContainsAny(v, "{}") {
+			return nil, p.errorAt(start, "unexpected brace in %q", v)
+		}
+		if v != "" {
+			list = append(list, v)
+		}
+	}
+	return list, nil
+}
+
+// quoted reads a string in single or double quotes. A backslash escapes the
+// character after it.
+func (p *parser) quoted() (string, error) {
+	p.skipSpace()
+	if p.pos == len(p.s) || (p.s[p.pos] != '"' && p.s[p.pos] != '\'') {
+		return "", p.errorAt(p.pos, "expected a quoted string")
+	}
+	q := p.s[p.pos]
+	start := p.pos
+	p.pos++
+
+	var b []byte
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		p.pos++
+		switch {
+		case c == q:
+			return string(b), nil
+		case c == '\\' && p.pos < len(p.s):
+			b = append(b, p.s[p.pos])
+			p.pos++
+		default:
+			b = append(b, c)
+		}
+	}
+	return "", p.errorAt(start, "unterminated string")
+}
+
+// quotedList reads a comma separated list of quoted strings up to ")".
+func (p *parser) quotedList() ([]string, error) {
+	var list []string
+	if p.accept(")") {
+		return list, nil
+	}
+	for {
+		v, err := p.quoted()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, v)
+		if p.accept(")") {
+			return list, nil
+		}
+		if err := p.literal(","); err != nil {
+			return nil, err
+		}
+	}
+}
@@ -0,0 +1,187 @@
+// Package query parses and renders the Datadog monitor queries managed by the
+// metric alert, service check and outlier alert resources, and the composite
+// queries of datadog_monitor.
+//
+// Parsing is lenient about spacing, quoting and the formatting of numbers,
+// rendering always produces the same canonical form. Two queries mean the same
+// thing when they render to the same string, which is how the resources tell a
+// change made in Datadog from a difference in formatting.
+package query
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+)
+
+// Query is a parsed monitor query.
+type Query interface {
+	// String renders the query in canonical form.
+	String() string
+}
+
+// Metric is a metric alert query, such as
+//
+//	avg(last_1h):avg:aws.ec2.cpu{environment:foo,host:foo} by {host} > 2
+type Metric struct {
+	TimeAggr   string
+	TimeWindow string
+	SpaceAggr  string
+	Metric     string
+	Scope      []string
+	By         []string
+	Operator   string
+	Threshold  string
+}
+
+func (q *Metric) String() string {
+	var b bytes.Buffer
+	b.WriteString(q.TimeAggr + "(" + q.TimeWindow + "):")
+	writeMetric(&b, q.SpaceAggr, q.Metric, q.Scope, q.By)
+	b.WriteString(" " + q.Operator + " " + number(q.Threshold))
+	return b.String()
+}
+
+// Outlier is an outlier alert query, such as
+//
+//	avg(last_1h):outliers(avg:system.load.5{*} by {host}, 'dbscan', 3) > 0
+type Outlier struct {
+	TimeAggr   string
+	TimeWindow string
+	SpaceAggr  string
+	Metric     string
+	Scope      []string
+	By         []string
+	Algorithm  string
+	Tolerance  string
+}
+
+func (q *Outlier) String() string {
+	var b bytes.Buffer
+	b.WriteString(q.TimeAggr + "(" + q.TimeWindow + "):outliers(")
+	writeMetric(&b, q.SpaceAggr, q.Metric, q.Scope, q.By)
+	b.WriteString(", '" + q.Algorithm + "', " + number(q.Tolerance) + ") > 0")
+	return b.String()
+}
+
+// ServiceCheck is a service check query, such as
+//
+//	"http.can_connect".over("instance:foo").by("host").last(2).count_by_status()
+type ServiceCheck struct {
+	Check string
+	Scope []string
+	By    []string
+	Last  string
+}
+
+func (q *ServiceCheck) String() string {
+	var b bytes.Buffer
+	b.WriteString(quote(q.Check) + ".over(")
+	writeQuoted(&b, normalizeScope(q.Scope))
+	b.WriteString(")")
+	if len(q.By) > 0 {
+		b.WriteString(".by(")
+		writeQuoted(&b, q.By)
+		b.WriteString(")")
+	}
+	b.WriteString(".last(" + number(q.Last) + ").count_by_status()")
+	return b.String()
+}
+
+// Comparison is a query written by hand, compared against a threshold. Expr
+// is kept as it is, apart from surrounding space.
+type Comparison struct {
+	Expr      string
+	Operator  string
+	Threshold string
+}
+
+func (q *Comparison) String() string {
+	return strings.TrimSpace(q.Expr) + " " + q.Operator + " " + number(q.Threshold)
+}
+
+// Composite is a composite monitor query, combining the states of other
+// monitors by ID, such as
+//
+//	123 && (456 || !789)
+//
+// Terms holds the IDs, operators and parentheses in order.
+type Composite struct {
+	Terms []string
+}
+
+func (q *Composite) String() string {
+	var b bytes.Buffer
+	for i, t := range q.Terms {
+		if i > 0 && t != ")" && q.Terms[i-1] != "!" && q.Terms[i-1] != "(" {
+			b.WriteString(" ")
+		}
+		b.WriteString(t)
+	}
+	return b.String()
+}
+
+// IDs returns the monitor IDs q refers to, in order of appearance and
+// without repeats.
+func (q *Composite) IDs() []int {
+	var ids []int
+	seen := make(map[int]bool)
+	for _, t := range q.Terms {
+		if id, err := strconv.Atoi(t); err == nil && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// Equal reports whether a and b render to the same canonical query.
+func Equal(a, b Query) bool {
+	return a.String() == b.String()
+}
+
+func writeMetric(b *bytes.Buffer, spaceAggr, metric string, scope, by []string) {
+	b.WriteString(spaceAggr + ":" + metric + "{" + strings.Join(normalizeScope(scope), ",") + "}")
+	if len(by) > 0 {
+		b.WriteString(" by {" + strings.Join(by, ",") + "}")
+	}
+}
+
+func writeQuoted(b *bytes.Buffer, list []string) {
+	for i, v := range list {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		b.WriteString(quote(v))
+	}
+}
+
+// normalizeScope returns scope in canonical form. An empty scope is "*", and
+// "*" next to other tags adds nothing, so it is dropped.
+func normalizeScope(scope []string) []string {
+	var tags []string
+	for _, t := range scope {
+		if t = strings.TrimSpace(t); t != "" && t != "*" {
+			tags = append(tags, t)
+		}
+	}
+	if len(tags) == 0 {
+		return []string{"*"}
+	}
+	return tags
+}
+
+// number returns s in canonical form, so 5, 5.0 and 5e0 render the same.
+// Anything that is not a number is kept as it is.
+func number(s string) string {
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return s
+	}
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func quote(s string) string {
+	s = strings.Replace(s, `\`, `\\`, -1)
+	return `"` + strings.Replace(s, `"`, `\"`, -1) + `"`
+}
@@ -0,0 +1,157 @@
+package query
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+func TestParse_Golden(t *testing.T) {
+	testGolden(t, "queries", func(s string) (Query, error) { return Parse(s) })
+}
+
+func TestParseComparison_Golden(t *testing.T) {
+	testGolden(t, "comparisons", func(s string) (Query, error) { return ParseComparison(s) })
+}
+
+// testGolden parses each line of testdata/name.txt and compares the canonical
+// query, or the error, with testdata/name.golden. Run with -update to rewrite
+// the golden file.
+func testGolden(t *testing.T, name string, parse func(string) (Query, error)) {
+	f, err := os.Open(filepath.Join("testdata", name+".txt"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer f.Close()
+
+	var out bytes.Buffer
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fmt.Fprintf(&out, "in:  %s\n", line)
+		q, err := parse(line)
+		if err != nil {
+			fmt.Fprintf(&out, "err: %s\n\n", err)
+			continue
+		}
+		fmt.Fprintf(&out, "out: %s\n\n", q)
+
+		again, err := parse(q.String())
+		if err != nil {
+			t.Fatalf("Expected %q to parse, got %s", q, err)
+		}
+		if !reflect.DeepEqual(q, again) {
+			t.Fatalf("Expected %q to parse back to %#v, got %#v", q, q, again)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	golden := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := ioutil.WriteFile(golden, out.Bytes(), 0644); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+	expected, err := ioutil.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !bytes.Equal(out.Bytes(), expected) {
+		t.Fatalf("Output does not match %s, run with -update if the change is intended:\n%s", golden, out.String())
+	}
+}
+
+func TestRender(t *testing.T) {
+	cases := []struct {
+		Query    Query
+		Expected string
+	}{
+		{
+			&Metric{TimeAggr: "avg", TimeWindow: "last_1h", SpaceAggr: "avg", Metric: "aws.ec2.cpu",
+				Scope: []string{"environment:foo", "host:foo"}, By: []string{"host"}, Operator: ">", Threshold: "2"},
+			"avg(last_1h):avg:aws.ec2.cpu{environment:foo,host:foo} by {host} > 2",
+		},
+		{
+			&Metric{TimeAggr: "avg", TimeWindow: "last_1h", SpaceAggr: "avg", Metric: "aws.ec2.cpu",
+				Operator: "<", Threshold: "2"},
+			"avg(last_1h):avg:aws.ec2.cpu{*} < 2",
+		},
+		{
+			&Metric{TimeAggr: "avg", TimeWindow: "last_1h", SpaceAggr: "avg", Metric: "aws.ec2.cpu",
+				Operator: ">", Threshold: "5.0"},
+			"avg(last_1h):avg:aws.ec2.cpu{*} > 5",
+		},
+		{
+			&Outlier{TimeAggr: "avg", TimeWindow: "last_1h", SpaceAggr: "avg", Metric: "system.load.5",
+				Scope: []string{"*", "host:foo"}, By: []string{"host"}, Algorithm: "dbscan", Tolerance: "3"},
+			"avg(last_1h):outliers(avg:system.load.5{host:foo} by {host}, 'dbscan', 3) > 0",
+		},
+		{
+			&ServiceCheck{Check: "http.can_connect", Scope: []string{"instance:foo"}, By: []string{"host", "url"}, Last: "2"},
+			`"http.can_connect".over("instance:foo").by("host","url").last(2).count_by_status()`,
+		},
+		{
+			&ServiceCheck{Check: "http.can_connect", Last: "2"},
+			`"http.can_connect".over("*").last(2).count_by_status()`,
+		},
+		{
+			&Comparison{Expr: " avg(last_1h):avg:aws.ec2.cpu{*} ", Operator: ">=", Threshold: "2"},
+			"avg(last_1h):avg:aws.ec2.cpu{*} >= 2",
+		},
+		{
+			&Composite{Terms: []string{"!", "(", "1", "||", "2", ")", "&&", "3"}},
+			"!(1 || 2) && 3",
+		},
+	}
+
+	for _, tc := range cases {
+		if s := tc.Query.String(); s != tc.Expected {
+			t.Fatalf("Expected %q, got %q", tc.Expected, s)
+		}
+		q, err := Parse(tc.Expected)
+		if _, ok := tc.Query.(*Comparison); ok {
+			q, err = ParseComparison(tc.Expected)
+		}
+		if err != nil {
+			t.Fatalf("Expected %q to parse, got %s", tc.Expected, err)
+		}
+		if !Equal(q, tc.Query) {
+			t.Fatalf("Expected %q to equal %q", q, tc.Query)
+		}
+	}
+}
+
+func TestSyntaxError(t *testing.T) {
+	_, err := Parse("avg(last_1h):avg:aws.ec2.cpu{*} > two")
+	serr, ok := err.(*SyntaxError)
+	if !ok {
+		t.Fatalf("Expected a *SyntaxError, got %#v", err)
+	}
+	if serr.Offset != 34 || serr.Msg != "expected a number" {
+		t.Fatalf("Unexpected error %s", serr)
+	}
+}
+
+func TestCompositeIDs(t *testing.T) {
+	q, err := ParseComposite("123 && (456 || !123)")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if ids := q.IDs(); !reflect.DeepEqual(ids, []int{123, 456}) {
+		t.Fatalf("Expected IDs [123 456], got %v", ids)
+	}
+}